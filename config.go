@@ -0,0 +1,161 @@
+package pool
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/gobwas/pool/internal/pmath"
+)
+
+// ConfigEnv is the name of environment variable containing pools settings.
+// Its value is either JSON object of Settings by pool names or a path to the
+// file containing such object. For example:
+//
+//   GOBWAS_POOL_CONFIG='{
+//       "pbytes.DefaultPool": {"min": 128, "max": 524288},
+//       "pool.DefaultPool":   {"sizes": [100, 200], "mapping": "identity"},
+//       "pbufio.DefaultWriterPool": {"stats": true}
+//   }'
+//
+// Settings are applied to the pools constructed with options returned by
+// ConfigOptions(), which is the case for the default pools of this module.
+const ConfigEnv = "GOBWAS_POOL_CONFIG"
+
+// maxSize is the maximum size allowed by Settings. Greater sizes could not be
+// ceiled to the next power of two.
+const maxSize = pmath.MaxPowerOfTwo
+
+// Settings describes pool configuration in serializable form.
+type Settings struct {
+	// Mapping is a size mapping mode. It is either "log" (the default) or
+	// "identity". See WithLogSizeMapping() and WithIdentitySizeMapping().
+	Mapping string `json:"mapping,omitempty"`
+
+	// Min and Max describe logarithmic range of sizes.
+	// It is ignored if Max is zero. See WithLogSizeRange().
+	Min int `json:"min,omitempty"`
+	Max int `json:"max,omitempty"`
+
+	// Sizes contains sizes added to the pool explicitly. See WithSize().
+	Sizes []int `json:"sizes,omitempty"`
+
+	// Stats enables counting of pool events by Counter. It is disabled by
+	// default to not add overhead to Get() and Put() calls.
+	Stats bool `json:"stats,omitempty"`
+}
+
+// Options returns Options described by s. It returns non-nil error if s is
+// malformed.
+//
+// Settings without sizes are allowed only if Stats is set. Options of such
+// settings do not describe pool sizes and must follow ones which do, as it is
+// done by ConfigOptions().
+func (s Settings) Options() ([]Option, error) {
+	var opts []Option
+	switch s.Mapping {
+	case "":
+		if s.sized() {
+			opts = append(opts, WithLogSizeMapping())
+		}
+	case "log":
+		opts = append(opts, WithLogSizeMapping())
+	case "identity":
+		opts = append(opts, WithIdentitySizeMapping())
+	default:
+		return nil, fmt.Errorf("unknown size mapping %q", s.Mapping)
+	}
+	if !s.sized() && !s.Stats {
+		return nil, fmt.Errorf("no sizes")
+	}
+	if s.Max != 0 {
+		if s.Min < 0 || s.Min > s.Max || s.Max > maxSize {
+			return nil, fmt.Errorf("invalid range [%d, %d]", s.Min, s.Max)
+		}
+		opts = append(opts, WithLogSizeRange(s.Min, s.Max))
+	}
+	for _, n := range s.Sizes {
+		if n < 0 || n > maxSize {
+			return nil, fmt.Errorf("invalid size %d", n)
+		}
+		opts = append(opts, WithSize(n))
+	}
+	if s.Stats {
+		opts = append(opts, WithObserver(new(Counter)))
+	}
+	return opts, nil
+}
+
+// sized reports whether s describes pool sizes.
+func (s Settings) sized() bool {
+	return s.Max != 0 || len(s.Sizes) != 0
+}
+
+// ParseSettings parses JSON object of Settings by pool names.
+func ParseSettings(data []byte) (map[string]Settings, error) {
+	var ret map[string]Settings
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&ret); err != nil {
+		return nil, fmt.Errorf("pool: parse settings: %v", err)
+	}
+	for name, s := range ret {
+		if _, err := s.Options(); err != nil {
+			return nil, fmt.Errorf("pool: settings of %q: %v", name, err)
+		}
+	}
+	return ret, nil
+}
+
+// LoadSettings returns settings from ConfigEnv environment variable.
+// It returns nil map and nil error if variable is empty.
+//
+// Programs could call it on start to report malformed settings, which are
+// ignored by ConfigOptions().
+func LoadSettings() (map[string]Settings, error) {
+	return loadSettings(os.Getenv(ConfigEnv))
+}
+
+func loadSettings(env string) (map[string]Settings, error) {
+	env = strings.TrimSpace(env)
+	if env == "" {
+		return nil, nil
+	}
+	data := []byte(env)
+	if !strings.HasPrefix(env, "{") {
+		var err error
+		if data, err = ioutil.ReadFile(env); err != nil {
+			return nil, fmt.Errorf("pool: load settings: %v", err)
+		}
+	}
+	return ParseSettings(data)
+}
+
+// settings are read once at initialization. Malformed settings are ignored;
+// the error could be inspected by LoadSettings() call.
+var settings, _ = LoadSettings()
+
+// ConfigOptions returns Options for the pool with given name. That is, it
+// returns WithName(name) option followed by the options described by
+// ConfigEnv environment variable for that name. If there are no settings for
+// the name, def options are returned instead. If settings have no sizes, they
+// are applied after def options.
+//
+// Note that ConfigEnv is read once at initialization. If its value is
+// malformed, def options are returned for any name; use LoadSettings() to
+// check the value.
+func ConfigOptions(name string, def ...Option) []Option {
+	opts := []Option{WithName(name)}
+	if s, has := settings[name]; has {
+		// Settings were validated by ParseSettings().
+		xs, _ := s.Options()
+		if !s.sized() {
+			opts = append(opts, def...)
+		}
+		return append(opts, xs...)
+	}
+	return append(opts, def...)
+}
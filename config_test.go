@@ -0,0 +1,186 @@
+package pool
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSettingsOptions(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		settings Settings
+		get      int
+		expSize  int
+		expSizes []int
+		err      bool
+	}{
+		{
+			name:     "log",
+			settings: Settings{Min: 2, Max: 16},
+			get:      5,
+			expSize:  8,
+			expSizes: []int{2, 4, 8, 16},
+		},
+		{
+			name:     "identity",
+			settings: Settings{Mapping: "identity", Sizes: []int{10, 100}},
+			get:      10,
+			expSize:  10,
+			expSizes: []int{10, 100},
+		},
+		{
+			name:     "range and sizes",
+			settings: Settings{Max: 4, Sizes: []int{100}},
+			get:      3,
+			expSize:  4,
+			expSizes: []int{1, 2, 4, 100},
+		},
+		{
+			name:     "stats",
+			settings: Settings{Max: 4, Stats: true},
+			get:      3,
+			expSize:  4,
+			expSizes: []int{1, 2, 4},
+		},
+		{
+			name:     "unknown mapping",
+			settings: Settings{Mapping: "linear", Max: 4},
+			err:      true,
+		},
+		{
+			name: "no sizes",
+			err:  true,
+		},
+		{
+			name:     "invalid range",
+			settings: Settings{Min: 8, Max: 4},
+			err:      true,
+		},
+		{
+			name:     "invalid size",
+			settings: Settings{Sizes: []int{-1}},
+			err:      true,
+		},
+		{
+			name:     "too large range",
+			settings: Settings{Max: maxSize + 1},
+			err:      true,
+		},
+		{
+			name:     "too large size",
+			settings: Settings{Sizes: []int{maxSize + 1}},
+			err:      true,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			opts, err := test.settings.Options()
+			if test.err {
+				if err == nil {
+					t.Fatalf("want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			p := Custom(opts...)
+			if _, ok := p.Observer().(*Counter); ok != test.settings.Stats {
+				t.Errorf("Observer() = %T; want Counter installed: %t", p.Observer(), test.settings.Stats)
+			}
+			if _, n := p.Get(test.get); n != test.expSize {
+				t.Errorf("Get(%d) size is %d; want %d", test.get, n, test.expSize)
+			}
+			if act := p.Sizes(); !reflect.DeepEqual(act, test.expSizes) {
+				t.Errorf("Sizes() = %v; want %v", act, test.expSizes)
+			}
+		})
+	}
+}
+
+func TestLoadSettings(t *testing.T) {
+	const data = `{"a": {"min": 1, "max": 8}, "b": {"sizes": [3]}}`
+	exp := map[string]Settings{
+		"a": {Min: 1, Max: 8},
+		"b": {Sizes: []int{3}},
+	}
+
+	dir, err := ioutil.TempDir("", "pool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "config.json")
+	if err := ioutil.WriteFile(file, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, env := range []string{data, file} {
+		act, err := loadSettings(env)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(act, exp) {
+			t.Errorf("loadSettings(%q) = %v; want %v", env, act, exp)
+		}
+	}
+	if act, err := loadSettings(""); act != nil || err != nil {
+		t.Errorf("loadSettings() = %v, %v; want nil, nil", act, err)
+	}
+	for _, env := range []string{
+		`{"a": {"mx": 8}}`,
+		`{"a": {"max": 8, "mapping": "linear"}}`,
+		`{"a": }`,
+		`{"a": {"max": 9223372036854775807}}`,
+		filepath.Join(dir, "missing.json"),
+	} {
+		if _, err := loadSettings(env); err == nil {
+			t.Errorf("loadSettings(%q) want error", env)
+		}
+	}
+}
+
+func TestConfigOptions(t *testing.T) {
+	defer func(prev map[string]Settings) {
+		settings = prev
+	}(settings)
+	settings = map[string]Settings{
+		"pool.test.configured": {Mapping: "identity", Sizes: []int{10}},
+		"pool.test.stats":      {Stats: true},
+	}
+
+	// Note that the first option is skipped to not register the pools.
+	opts := ConfigOptions("pool.test.configured", WithLogSizeRange(0, 8))
+	var named Pool
+	opts[0](&poolConfig{pool: &named, init: true})
+	if name := named.Name(); name != "pool.test.configured" {
+		t.Errorf("Name() = %q; want %q", name, "pool.test.configured")
+	}
+	p := Custom(opts[1:]...)
+	if act, exp := p.Sizes(), []int{10}; !reflect.DeepEqual(act, exp) {
+		t.Errorf("Sizes() = %v; want %v", act, exp)
+	}
+
+	opts = ConfigOptions("pool.test.default", WithLogSizeRange(0, 2))
+	p = Custom(opts[1:]...)
+	if act, exp := p.Sizes(), []int{1, 2}; !reflect.DeepEqual(act, exp) {
+		t.Errorf("Sizes() = %v; want %v", act, exp)
+	}
+	if p.Observer() != nil {
+		t.Errorf("unexpected Observer() of default pool: %T", p.Observer())
+	}
+
+	opts = ConfigOptions("pool.test.stats", WithIdentitySizeMapping(), WithSize(3))
+	p = Custom(opts[1:]...)
+	if act, exp := p.Sizes(), []int{3}; !reflect.DeepEqual(act, exp) {
+		t.Errorf("Sizes() = %v; want %v", act, exp)
+	}
+	if _, n := p.Get(3); n != 3 {
+		t.Errorf("Get(3) size is %d; want 3 by default identity mapping", n)
+	}
+	if _, ok := p.Observer().(*Counter); !ok {
+		t.Errorf("Observer() = %T; want *Counter", p.Observer())
+	}
+}
@@ -0,0 +1,77 @@
+package pool
+
+import "sync/atomic"
+
+// Counter is an Observer which counts pool events.
+// Its zero value is ready to use.
+//
+// Note that Counter could be installed to the default pools of this module by
+// Settings.Stats.
+type Counter struct {
+	gets        uint64
+	hits        uint64
+	allocs      uint64
+	puts        uint64
+	drops       uint64
+	putBytes    uint64
+	reusedBytes uint64
+}
+
+// Stats contains pool events counters. All counters are cumulative since the
+// pool creation.
+type Stats struct {
+	Gets   uint64 `json:"gets"`
+	Hits   uint64 `json:"hits"`
+	Misses uint64 `json:"misses"`
+	Allocs uint64 `json:"allocs"`
+	Puts   uint64 `json:"puts"`
+	Drops  uint64 `json:"drops"`
+
+	// PutBytes is a sum of sizes of the objects taken for reuse.
+	PutBytes uint64 `json:"put_bytes"`
+
+	// ReusedBytes is a sum of sizes of the reused objects.
+	//
+	// Note that PutBytes-ReusedBytes is only an upper bound of the size of
+	// objects retained by the pool, since the pool drops its objects on GC.
+	ReusedBytes uint64 `json:"reused_bytes"`
+}
+
+// Stats returns snapshot of the counters.
+func (c *Counter) Stats() Stats {
+	s := Stats{
+		Gets:        atomic.LoadUint64(&c.gets),
+		Hits:        atomic.LoadUint64(&c.hits),
+		Allocs:      atomic.LoadUint64(&c.allocs),
+		Puts:        atomic.LoadUint64(&c.puts),
+		Drops:       atomic.LoadUint64(&c.drops),
+		PutBytes:    atomic.LoadUint64(&c.putBytes),
+		ReusedBytes: atomic.LoadUint64(&c.reusedBytes),
+	}
+	s.Misses = s.Gets - s.Hits
+	return s
+}
+
+// OnGet implements Observer.
+func (c *Counter) OnGet(size, class int, hit bool) {
+	atomic.AddUint64(&c.gets, 1)
+	if hit {
+		atomic.AddUint64(&c.hits, 1)
+		atomic.AddUint64(&c.reusedBytes, uint64(class))
+	}
+}
+
+// OnPut implements Observer.
+func (c *Counter) OnPut(size int, accepted bool) {
+	atomic.AddUint64(&c.puts, 1)
+	if accepted {
+		atomic.AddUint64(&c.putBytes, uint64(size))
+	} else {
+		atomic.AddUint64(&c.drops, 1)
+	}
+}
+
+// OnAlloc implements Observer.
+func (c *Counter) OnAlloc(size int) {
+	atomic.AddUint64(&c.allocs, 1)
+}
@@ -0,0 +1,30 @@
+package pool
+
+import "testing"
+
+func TestCounter(t *testing.T) {
+	var c Counter
+	p := Custom(
+		WithLogSizeMapping(),
+		WithLogSizeRange(0, 64),
+		WithObserver(&c),
+	)
+	x, n := p.Get(10)
+	if x == nil {
+		x = new([16]byte)
+	}
+	p.Put(x, n)
+	p.Put(new([100]byte), 100)
+
+	exp := Stats{
+		Gets:     1,
+		Misses:   1,
+		Allocs:   1,
+		Puts:     2,
+		Drops:    1,
+		PutBytes: 16,
+	}
+	if act := c.Stats(); act != exp {
+		t.Errorf("unexpected stats: %+v; want %+v", act, exp)
+	}
+}
@@ -1,12 +1,52 @@
 package pool
 
 import (
+	"sort"
 	"sync"
+	"sync/atomic"
 
 	"github.com/gobwas/pool/internal/pmath"
 )
 
-var DefaultPool = New(128, 65536)
+// DefaultPool is the initial default pool used by package level functions.
+// It is registered by "pool.DefaultPool" name and could be configured by
+// ConfigEnv environment variable. Its events could be counted by Counter
+// enabled by Settings.Stats.
+//
+// Note that assigning to DefaultPool does not change the pool used by package
+// level functions; use SetDefault() instead.
+var DefaultPool = Custom(ConfigOptions("pool.DefaultPool",
+	WithLogSizeMapping(),
+	WithLogSizeRange(128, 65536),
+)...)
+
+var current struct {
+	mu sync.Mutex
+	v  atomic.Value
+}
+
+func init() {
+	current.v.Store(DefaultPool)
+}
+
+// Default returns the pool currently used by package level functions.
+func Default() *Pool {
+	return current.v.Load().(*Pool)
+}
+
+// SetDefault makes p the pool used by package level functions and returns
+// the previous one. It is safe to call SetDefault concurrently with package
+// level functions. It panics if p is nil.
+func SetDefault(p *Pool) (prev *Pool) {
+	if p == nil {
+		panic("pool: nil default pool")
+	}
+	current.mu.Lock()
+	defer current.mu.Unlock()
+	prev = Default()
+	current.v.Store(p)
+	return prev
+}
 
 // Get pulls object whose generic size is at least of given size. It also
 // returns a real size of x for further pass to Put(). It returns -1 as real
@@ -15,16 +55,40 @@ var DefaultPool = New(128, 65536)
 //
 // Note that size could be ceiled to the next power of two.
 //
-// Get is a wrapper around DefaultPool.Get().
-func Get(size int) (interface{}, int) { return DefaultPool.Get(size) }
+// Get is a wrapper around Default().Get().
+func Get(size int) (interface{}, int) { return Default().Get(size) }
 
 // Put takes x and its size for future reuse.
-// Put is a wrapper around DefaultPool.Put().
-func Put(x interface{}, size int) { DefaultPool.Put(x, size) }
+// Put is a wrapper around Default().Put().
+func Put(x interface{}, size int) { Default().Put(x, size) }
+
+// Sizer is the interface that objects stored in the Pool could implement to
+// report their real size. See WithSizer().
+type Sizer interface {
+	Size() int
+}
+
+// Resetter is the interface that objects stored in the Pool could implement
+// to be reset when they are taken for future reuse. See WithResetter().
+type Resetter interface {
+	Reset()
+}
 
 // Pool contains logic of reusing objects distinguishable by size in generic
 // way.
 type Pool struct {
+	name     string
+	table    atomic.Value // *table
+	mu       sync.Mutex   // Serializes Reconfigure() calls.
+	sizeFunc func(interface{}) int
+	reset    func(interface{})
+	observer Observer
+	tracker  Tracker
+}
+
+// table maps sizes to the pools of objects of that size. It is not changed
+// after being stored in the Pool; Reconfigure() replaces it with a new one.
+type table struct {
 	pool map[int]*sync.Pool
 	size func(int) int
 }
@@ -42,16 +106,25 @@ func New(min, max int) *Pool {
 }
 
 // Custom creates new Pool with given options.
+// If the pool is named by WithName() option, it is added to the registry of
+// named pools. See Lookup() and Range().
 func Custom(opts ...Option) *Pool {
-	p := &Pool{
-		pool: make(map[int]*sync.Pool),
-		size: pmath.Identity,
+	p := new(Pool)
+	c := &poolConfig{
+		pool: p,
+		table: &table{
+			pool: make(map[int]*sync.Pool),
+			size: pmath.Identity,
+		},
+		init: true,
 	}
-
-	c := (*poolConfig)(p)
 	for _, opt := range opts {
 		opt(c)
 	}
+	p.table.Store(c.table)
+	if p.name != "" {
+		register(p)
+	}
 
 	return p
 }
@@ -60,28 +133,169 @@ func Custom(opts ...Option) *Pool {
 // It also returns a real size of x for further pass to Put() even if x is nil.
 // Note that size could be ceiled to the next power of two.
 func (p *Pool) Get(size int) (interface{}, int) {
-	n := p.size(size)
-	if pool := p.pool[n]; pool != nil {
-		return pool.Get(), n
+	t := p.load()
+	n := t.size(size)
+	if pool := t.pool[n]; pool != nil {
+		x := pool.Get()
+		if p.observer != nil {
+			p.observer.OnGet(size, n, x != nil)
+			if x == nil {
+				p.observer.OnAlloc(n)
+			}
+		}
+		if p.tracker != nil && x != nil {
+			p.tracker.OnAcquire(x)
+		}
+		return x, n
+	}
+	if p.observer != nil {
+		p.observer.OnGet(size, -1, false)
+		p.observer.OnAlloc(size)
 	}
 	return nil, size
 }
 
 // Put takes x and its size for future reuse.
+// If Pool is configured to compute real size of objects, x is not reused when
+// its real size is not equal to the given one.
+// If x is going to be reused, it is reset by the function configured by
+// WithReset() or WithResetter() options.
 func (p *Pool) Put(x interface{}, size int) {
-	if pool := p.pool[size]; pool != nil {
-		pool.Put(x)
+	if p.tracker != nil {
+		p.tracker.OnRelease(x)
+	}
+	accepted := p.put(x, size)
+	if p.observer != nil {
+		p.observer.OnPut(size, accepted)
 	}
 }
 
-type poolConfig Pool
+func (p *Pool) put(x interface{}, size int) bool {
+	pool := p.load().pool[size]
+	if pool == nil {
+		return false
+	}
+	if p.sizeFunc != nil {
+		if n := p.sizeFunc(x); n >= 0 && n != size {
+			return false
+		}
+	}
+	if p.reset != nil {
+		p.reset(x)
+	}
+	pool.Put(x)
+	return true
+}
+
+// Name returns the name given to the Pool by WithName() option.
+func (p *Pool) Name() string {
+	return p.name
+}
+
+// Sizes returns sorted list of sizes reused by the Pool.
+func (p *Pool) Sizes() []int {
+	t := p.load()
+	sizes := make([]int, 0, len(t.pool))
+	for n := range t.pool {
+		sizes = append(sizes, n)
+	}
+	sort.Ints(sizes)
+	return sizes
+}
+
+// Observer returns Observer installed by WithObserver() option.
+// It returns nil if there is no Observer installed.
+func (p *Pool) Observer() Observer {
+	return p.observer
+}
+
+// Tracker returns Observer installed by WithObserver() option if it
+// implements Tracker. It returns nil otherwise.
+//
+// Objects allocated by the caller when Get() returns nil must be reported to
+// the Tracker by its OnAcquire() method to be tracked.
+func (p *Pool) Tracker() Tracker {
+	return p.tracker
+}
+
+// Reconfigure atomically replaces size classes of the Pool with the ones
+// described by given options. It is safe to call Reconfigure concurrently
+// with Get() and Put().
+//
+// Objects of sizes present in both old and new configurations stay available
+// for reuse; objects of removed sizes are dropped. Size mapping is inherited
+// unless it is changed by opts. Options other than WithSize(),
+// WithLogSizeRange() and size mapping ones have no effect.
+func (p *Pool) Reconfigure(opts ...Option) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	prev := p.load()
+	c := &poolConfig{
+		pool: p,
+		table: &table{
+			pool: make(map[int]*sync.Pool),
+			size: prev.size,
+		},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	for n := range c.table.pool {
+		if pool := prev.pool[n]; pool != nil {
+			c.table.pool[n] = pool
+		}
+	}
+	p.table.Store(c.table)
+}
+
+func (p *Pool) load() *table {
+	return p.table.Load().(*table)
+}
+
+// poolConfig implements Config for the Pool. It changes only the table
+// unless init is true, that is, unless the Pool is under construction.
+type poolConfig struct {
+	pool  *Pool
+	table *table
+	init  bool
+}
+
+// SetName sets up name of the pool.
+func (c *poolConfig) SetName(name string) {
+	if c.init {
+		c.pool.name = name
+	}
+}
 
 // AddSize adds size n to the map.
-func (p *poolConfig) AddSize(n int) {
-	p.pool[n] = new(sync.Pool)
+func (c *poolConfig) AddSize(n int) {
+	c.table.pool[n] = new(sync.Pool)
 }
 
 // SetSizeMapping sets up incoming size mapping function.
-func (p *poolConfig) SetSizeMapping(size func(int) int) {
-	p.size = size
+func (c *poolConfig) SetSizeMapping(size func(int) int) {
+	c.table.size = size
+}
+
+// SetSizeFunc sets up function computing real size of objects.
+func (c *poolConfig) SetSizeFunc(fn func(interface{}) int) {
+	if c.init {
+		c.pool.sizeFunc = fn
+	}
+}
+
+// SetReset sets up function resetting objects on Put().
+func (c *poolConfig) SetReset(fn func(interface{})) {
+	if c.init {
+		c.pool.reset = fn
+	}
+}
+
+// SetObserver sets up pool events observer.
+func (c *poolConfig) SetObserver(o Observer) {
+	if c.init {
+		c.pool.observer = o
+		c.pool.tracker, _ = o.(Tracker)
+	}
 }
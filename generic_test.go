@@ -1,6 +1,9 @@
 package pool
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+)
 
 func TestGenericPoolGet(t *testing.T) {
 	for _, test := range []struct {
@@ -31,3 +34,198 @@ func TestGenericPoolGet(t *testing.T) {
 		})
 	}
 }
+
+func TestGenericPoolPutSizeFunc(t *testing.T) {
+	var c Counter
+	p := Custom(
+		WithLogSizeMapping(),
+		WithLogSizeRange(0, 4096),
+		WithSizeFunc(func(x interface{}) int {
+			return cap(x.([]byte))
+		}),
+		WithObserver(&c),
+	)
+
+	p.Put(make([]byte, 128), 4096) // Should not reuse.
+	if x, _ := p.Get(4000); x != nil {
+		t.Fatalf("unexpected reuse of object with mismatched size")
+	}
+
+	p.Put(make([]byte, 4096), 4096) // Should reuse.
+	if s := c.Stats(); s.Puts != 2 || s.Drops != 1 {
+		t.Fatalf("want object to be taken for reuse; stats are %+v", s)
+	}
+}
+
+type sizer int
+
+func (s *sizer) Size() int { return int(*s) }
+
+func TestGenericPoolPutSizer(t *testing.T) {
+	var c Counter
+	p := Custom(
+		WithLogSizeMapping(),
+		WithLogSizeRange(0, 4096),
+		WithSizer(),
+		WithObserver(&c),
+	)
+
+	small := sizer(128)
+	p.Put(&small, 4096) // Should not reuse.
+	if x, _ := p.Get(4000); x != nil {
+		t.Fatalf("unexpected reuse of object with mismatched size")
+	}
+
+	p.Put("unknown size", 4096) // Should reuse.
+	if s := c.Stats(); s.Puts != 2 || s.Drops != 1 {
+		t.Fatalf("want object with unknown size to be taken for reuse; stats are %+v", s)
+	}
+}
+
+// sizesConfig implements only required methods of Config.
+type sizesConfig struct {
+	sizes []int
+}
+
+func (c *sizesConfig) AddSize(n int)                { c.sizes = append(c.sizes, n) }
+func (c *sizesConfig) SetSizeMapping(func(int) int) {}
+
+func TestOptionalConfig(t *testing.T) {
+	var c sizesConfig
+	for _, opt := range []Option{
+		WithName("ignored"),
+		WithSize(42),
+		WithSizer(),
+		WithResetter(),
+		WithObserver(new(Counter)),
+	} {
+		opt(&c)
+	}
+	if exp := []int{42}; !reflect.DeepEqual(c.sizes, exp) {
+		t.Errorf("unexpected sizes: %v; want %v", c.sizes, exp)
+	}
+}
+
+type resetter struct {
+	dirty bool
+}
+
+func (r *resetter) Reset() { r.dirty = false }
+
+func TestGenericPoolPutReset(t *testing.T) {
+	p := New(0, 16)
+	r := &resetter{dirty: true}
+	p.Put(r, 16)
+	if !r.dirty {
+		t.Errorf("Resetter was reset without WithResetter() option")
+	}
+
+	p = Custom(
+		WithSize(16),
+		WithResetter(),
+	)
+	p.Put(r, 16)
+	if r.dirty {
+		t.Errorf("Resetter was not reset on Put()")
+	}
+
+	var reset []interface{}
+	p = Custom(
+		WithSize(16),
+		WithReset(func(x interface{}) {
+			reset = append(reset, x)
+		}),
+	)
+	r.dirty = true
+	p.Put(r, 16)
+	p.Put(r, 32) // Should not reset.
+	if !r.dirty {
+		t.Errorf("Resetter was reset by pool without WithResetter() option")
+	}
+	if len(reset) != 1 || reset[0] != r {
+		t.Errorf("unexpected objects reset by WithReset() function: %v", reset)
+	}
+}
+
+func TestGenericPoolSizes(t *testing.T) {
+	p := Custom(
+		WithLogSizeRange(4, 16),
+		WithSize(100),
+		WithSize(1),
+	)
+	if act, exp := p.Sizes(), []int{1, 4, 8, 16, 100}; !reflect.DeepEqual(act, exp) {
+		t.Errorf("Sizes() = %v; want %v", act, exp)
+	}
+}
+
+func TestSetDefault(t *testing.T) {
+	p := New(0, 8)
+	if prev := SetDefault(p); prev != DefaultPool {
+		t.Errorf("SetDefault() = %p; want DefaultPool", prev)
+	}
+	defer SetDefault(DefaultPool)
+
+	if act := Default(); act != p {
+		t.Errorf("Default() = %p; want %p", act, p)
+	}
+	_, n := Get(5)
+	if n != 8 {
+		t.Errorf("Get() uses pool other than the default one")
+	}
+	if prev := SetDefault(DefaultPool); prev != p {
+		t.Errorf("SetDefault() = %p; want %p", prev, p)
+	}
+}
+
+func TestGenericPoolReconfigure(t *testing.T) {
+	p := Custom(
+		WithLogSizeMapping(),
+		WithLogSizeRange(1, 8),
+		WithReset(func(interface{}) {}),
+	)
+	kept := new(int)
+	p.Put(kept, 4)
+
+	p.Reconfigure(WithLogSizeRange(4, 32))
+	if act, exp := p.Sizes(), []int{4, 8, 16, 32}; !reflect.DeepEqual(act, exp) {
+		t.Errorf("Sizes() = %v; want %v", act, exp)
+	}
+	if _, n := p.Get(10); n != 16 {
+		t.Errorf("Get(10) size is %d; want 16 (inherited log mapping)", n)
+	}
+	if _, n := p.Get(2); n != 2 {
+		t.Errorf("Get(2) size is %d; want 2 (removed class)", n)
+	}
+	if p.reset == nil {
+		t.Errorf("Reconfigure() dropped reset function")
+	}
+	// Note that sync.Pool may drop objects at any time, so we check only
+	// that the class which is present in both configurations stays the same.
+	if x, _ := p.Get(4); x != nil && x != kept {
+		t.Errorf("Get(4) = %v; want reused object", x)
+	}
+
+	p.Reconfigure(WithIdentitySizeMapping(), WithSize(10))
+	if _, n := p.Get(10); n != 10 {
+		t.Errorf("Get(10) size is %d; want 10", n)
+	}
+}
+
+func TestGenericPoolReconfigureConcurrent(t *testing.T) {
+	p := New(1, 64)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 1000; i++ {
+			x, n := p.Get(i % 100)
+			if x == nil {
+				x = new(int)
+			}
+			p.Put(x, n)
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		p.Reconfigure(WithLogSizeRange(1, 1<<uint(i%10)))
+	}
+	<-done
+}
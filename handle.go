@@ -0,0 +1,93 @@
+package pool
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Handle binds object pulled from the Pool to its size class and the Pool
+// itself. That is, object could be returned to the Pool without passing its
+// size around.
+//
+// Handle is a small value which could be copied; all copies refer to the same
+// object. Handle state is reused after Release(), so Acquire() does not
+// allocate.
+type Handle struct {
+	h    *handle
+	gen  uint32
+	size int
+}
+
+// handle holds state of the Handle. It is reused after Release(); gen is
+// incremented on each release to make stale Handle copies detectable.
+type handle struct {
+	pool  *Pool
+	value interface{}
+	gen   uint32
+}
+
+var handles = sync.Pool{
+	New: func() interface{} {
+		return new(handle)
+	},
+}
+
+// Acquire returns Handle of object whose generic size is at least of given
+// size. If Pool has no object to reuse, Handle's Value() returns nil; in that
+// case caller should create object of Handle's Size() and bind it by Set().
+//
+// Note that size could be ceiled to the next power of two.
+func (p *Pool) Acquire(size int) Handle {
+	x, n := p.Get(size)
+	h := handles.Get().(*handle)
+	h.pool = p
+	h.value = x
+	return Handle{
+		h:    h,
+		gen:  atomic.LoadUint32(&h.gen),
+		size: n,
+	}
+}
+
+// Value returns object bound to the handle. It returns nil after Release().
+func (h Handle) Value() interface{} {
+	if !h.valid() {
+		return nil
+	}
+	return h.h.value
+}
+
+// Size returns real size of the object bound to the handle.
+func (h Handle) Size() int {
+	return h.size
+}
+
+// Set binds x to the handle. Size of x must be equal to Handle's Size().
+// It panics if handle is already released.
+func (h Handle) Set(x interface{}) {
+	if !h.valid() {
+		panic("pool: set of released handle")
+	}
+	if t := h.h.pool.tracker; t != nil && h.h.value == nil {
+		t.OnAcquire(x)
+	}
+	h.h.value = x
+}
+
+// Release returns object bound to the handle to the Pool it was pulled from.
+// It panics if handle is released more than once.
+func (h Handle) Release() {
+	if h.h == nil || !atomic.CompareAndSwapUint32(&h.h.gen, h.gen, h.gen+1) {
+		panic("pool: handle released more than once")
+	}
+	p, x := h.h.pool, h.h.value
+	h.h.pool, h.h.value = nil, nil
+	handles.Put(h.h)
+	if x != nil {
+		p.Put(x, h.size)
+	}
+}
+
+func (h Handle) valid() bool {
+	return h.h != nil && atomic.LoadUint32(&h.h.gen) == h.gen
+}
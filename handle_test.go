@@ -0,0 +1,81 @@
+package pool
+
+import "testing"
+
+func TestHandle(t *testing.T) {
+	p := New(0, 16)
+
+	h := p.Acquire(10)
+	if h.Value() != nil {
+		t.Fatalf("unexpected value of empty pool handle")
+	}
+	if n := h.Size(); n != 16 {
+		t.Fatalf("Size() = %d; want %d", n, 16)
+	}
+	x := new([16]byte)
+	h.Set(x)
+	h.Release()
+	if h.Value() != nil {
+		t.Fatalf("released handle returns value")
+	}
+
+	// Note that sync.Pool may drop x (e.g. under the race detector).
+	h = p.Acquire(16)
+	if v := h.Value(); v != nil && v != x {
+		t.Fatalf("unexpected value of reused handle")
+	}
+	h.Release()
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("want panic on second Release()")
+		}
+	}()
+	h.Release()
+}
+
+func TestHandleStale(t *testing.T) {
+	p := New(0, 16)
+
+	h := p.Acquire(10)
+	h.Set(new([16]byte))
+	stale := h
+	h.Release()
+
+	// Handle state could be reused by the next Acquire() call.
+	next := p.Acquire(10)
+	if next.Value() == nil {
+		next.Set(new([16]byte))
+	}
+	if stale.Value() != nil {
+		t.Errorf("stale handle returns value")
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("want panic on Release() of stale handle")
+			}
+		}()
+		stale.Release()
+	}()
+	if next.Value() == nil {
+		t.Errorf("Release() of stale handle affected the next one")
+	}
+	next.Release()
+}
+
+func TestHandleAllocs(t *testing.T) {
+	p := New(0, 16)
+	x := new([16]byte)
+	p.Put(x, 16)
+	n := testing.AllocsPerRun(100, func() {
+		h := p.Acquire(16)
+		if h.Value() == nil {
+			h.Set(x)
+		}
+		h.Release()
+	})
+	if n > 0 {
+		t.Errorf("Acquire() and Release() make %v allocations; want 0", n)
+	}
+}
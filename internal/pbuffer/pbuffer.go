@@ -0,0 +1,75 @@
+// Package pbuffer contains growing buffer whose memory is taken from
+// pbytes.Default().
+package pbuffer
+
+import (
+	"io"
+
+	"github.com/gobwas/pool/pbytes"
+)
+
+// Buffer is a growing buffer whose memory is taken from pbytes.Default().
+// Its zero value is ready to use.
+type Buffer struct {
+	bts []byte
+}
+
+// Bytes returns buffered bytes. Returned slice is valid until the next
+// buffer modification.
+func (b *Buffer) Bytes() []byte {
+	return b.bts
+}
+
+// Detach returns buffered bytes and resets the buffer. The caller takes
+// ownership of returned slice and should return it to pbytes.Default() when
+// it is not nil.
+func (b *Buffer) Detach() []byte {
+	bts := b.bts
+	b.bts = nil
+	return bts
+}
+
+// Release returns buffered bytes to pbytes.Default() and resets the buffer.
+func (b *Buffer) Release() {
+	if b.bts != nil {
+		pbytes.Put(b.bts)
+		b.bts = nil
+	}
+}
+
+// Write implements io.Writer.
+func (b *Buffer) Write(p []byte) (int, error) {
+	b.Grow(len(p))
+	b.bts = append(b.bts, p...)
+	return len(p), nil
+}
+
+// ReadFrom implements io.ReaderFrom.
+func (b *Buffer) ReadFrom(r io.Reader) (n int64, err error) {
+	for {
+		b.Grow(512)
+		var m int
+		m, err = r.Read(b.bts[len(b.bts):cap(b.bts)])
+		b.bts = b.bts[:len(b.bts)+m]
+		n += int64(m)
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+	}
+}
+
+// Grow makes buffer to have at least n bytes of free capacity.
+func (b *Buffer) Grow(n int) {
+	if cap(b.bts)-len(b.bts) >= n {
+		return
+	}
+	bts := pbytes.GetCap(2*cap(b.bts) + n)
+	bts = append(bts, b.bts...)
+	if b.bts != nil {
+		pbytes.Put(b.bts)
+	}
+	b.bts = bts
+}
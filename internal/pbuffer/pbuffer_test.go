@@ -0,0 +1,30 @@
+package pbuffer
+
+import (
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestBuffer(t *testing.T) {
+	data := strings.Repeat("hello, world\n", 1000)
+
+	var b Buffer
+	if _, err := b.Write([]byte(data[:100])); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.ReadFrom(iotest.HalfReader(strings.NewReader(data[100:]))); err != nil {
+		t.Fatal(err)
+	}
+	if act := string(b.Bytes()); act != data {
+		t.Fatalf("unexpected buffered data")
+	}
+	bts := b.Detach()
+	if string(bts) != data {
+		t.Fatalf("unexpected detached data")
+	}
+	if b.Bytes() != nil {
+		t.Fatalf("detached buffer holds data")
+	}
+	b.Release() // Must not panic on empty buffer.
+}
@@ -6,6 +6,9 @@ const (
 	maxintHeadBit = 1 << (bitsize - 2)
 )
 
+// MaxPowerOfTwo is the greatest power of two int value.
+const MaxPowerOfTwo = maxintHeadBit
+
 // LogarithmicRange iterates from ceiled to power of two min to max,
 // calling cb on each iteration.
 func LogarithmicRange(min, max int, cb func(int)) {
@@ -0,0 +1,53 @@
+package pool
+
+// Observer is the interface of pool events listener. It could be installed
+// by WithObserver() option.
+//
+// Observer methods are called synchronously within Get() and Put() calls, so
+// implementation must be cheap and safe for concurrent use.
+type Observer interface {
+	// OnGet is called on every Get() call with requested size and the size
+	// class it was mapped to. Class is -1 if size is out of pool classes.
+	// Hit reports whether the object was reused.
+	OnGet(size, class int, hit bool)
+
+	// OnPut is called on every Put() call with given size. Accepted reports
+	// whether the object was taken for future reuse.
+	OnPut(size int, accepted bool)
+
+	// OnAlloc is called when Get() returns nil object, that is, when caller
+	// is expected to allocate a new object of given size.
+	OnAlloc(size int)
+}
+
+// Tracker is an optional interface that Observer could implement to follow
+// objects passing through the pool.
+//
+// Note that objects allocated by the caller after Get() returned nil are
+// passed to the Tracker only when they are bound to an empty Handle by Set()
+// or are reported by the caller itself. See Pool.Tracker().
+type Tracker interface {
+	// OnAcquire is called with object which is reused by Get() or which is
+	// bound to an empty Handle by Set(). Callers of Get() also call it with
+	// objects they allocate, as pbytes and pbufio do.
+	OnAcquire(x interface{})
+
+	// OnRelease is called with every object passed to Put().
+	OnRelease(x interface{})
+}
+
+// ObserverConfig is an optional interface of Config supporting
+// WithObserver() option.
+type ObserverConfig interface {
+	SetObserver(Observer)
+}
+
+// WithObserver returns an Option that installs given Observer of pool events.
+// If o also implements Tracker, it is used to follow pooled objects.
+func WithObserver(o Observer) Option {
+	return func(c Config) {
+		if oc, ok := c.(ObserverConfig); ok {
+			oc.SetObserver(o)
+		}
+	}
+}
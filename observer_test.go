@@ -0,0 +1,62 @@
+package pool
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type recorder struct {
+	events []string
+}
+
+func (r *recorder) OnGet(size, class int, hit bool) {
+	r.events = append(r.events, fmt.Sprintf("get(%d, %d, %t)", size, class, hit))
+}
+
+func (r *recorder) OnPut(size int, accepted bool) {
+	r.events = append(r.events, fmt.Sprintf("put(%d, %t)", size, accepted))
+}
+
+func (r *recorder) OnAlloc(size int) {
+	r.events = append(r.events, fmt.Sprintf("alloc(%d)", size))
+}
+
+func TestObserver(t *testing.T) {
+	var rec recorder
+	p := Custom(
+		WithLogSizeMapping(),
+		WithLogSizeRange(0, 16),
+		WithObserver(&rec),
+	)
+
+	x, n := p.Get(10)
+	if x == nil {
+		x = new([16]byte)
+	}
+	p.Put(x, n)
+	// Note that sync.Pool may drop x (e.g. under the race detector), so the
+	// next Get() could miss.
+	y, _ := p.Get(16)
+	p.Get(100)
+	p.Put(x, 100)
+
+	exp := []string{
+		"get(10, 16, false)",
+		"alloc(16)",
+		"put(16, true)",
+	}
+	if y != nil {
+		exp = append(exp, "get(16, 16, true)")
+	} else {
+		exp = append(exp, "get(16, 16, false)", "alloc(16)")
+	}
+	exp = append(exp,
+		"get(100, -1, false)",
+		"alloc(100)",
+		"put(100, false)",
+	)
+	if !reflect.DeepEqual(rec.events, exp) {
+		t.Errorf("unexpected events:\n%q\nwant:\n%q", rec.events, exp)
+	}
+}
@@ -6,11 +6,47 @@ import "github.com/gobwas/pool/internal/pmath"
 type Option func(Config)
 
 // Config describes generic pool configuration.
+//
+// Options which are not expressible by Config methods require Config to
+// implement optional interfaces such as NameConfig, SizeFuncConfig or
+// ResetConfig. Such options have no effect on Config not implementing them.
 type Config interface {
 	AddSize(n int)
 	SetSizeMapping(func(int) int)
 }
 
+// NameConfig is an optional interface of Config supporting WithName() option.
+type NameConfig interface {
+	SetName(string)
+}
+
+// SizeFuncConfig is an optional interface of Config supporting WithSizeFunc()
+// and WithSizer() options.
+type SizeFuncConfig interface {
+	SetSizeFunc(func(interface{}) int)
+}
+
+// ResetConfig is an optional interface of Config supporting WithReset() and
+// WithResetter() options.
+type ResetConfig interface {
+	SetReset(func(interface{}))
+}
+
+// WithName returns an Option that gives a name to the pool. Named pools are
+// registered and could be found by Lookup() or Range() calls. Constructor
+// panics if there is a pool with the same name already registered.
+//
+// Note that registry holds named pools until they are unregistered by
+// Unregister() call; so pools living less than the process (e.g. created in
+// tests or per tenant) must be unregistered when they are not needed anymore.
+func WithName(name string) Option {
+	return func(c Config) {
+		if nc, ok := c.(NameConfig); ok {
+			nc.SetName(name)
+		}
+	}
+}
+
 // WithSizeLogRange returns an Option that will add logarithmic range of
 // pooling sizes containing [min, max] values.
 func WithLogSizeRange(min, max int) Option {
@@ -41,3 +77,52 @@ func WithLogSizeMapping() Option {
 func WithIdentitySizeMapping() Option {
 	return WithSizeMapping(pmath.Identity)
 }
+
+// WithSizeFunc returns an Option that makes pool to compute real size of
+// objects passed to Put() by given function. Objects whose real size is not
+// equal to the size passed to Put() are not reused. If fn returns negative
+// value, object size is considered unknown and is not verified.
+func WithSizeFunc(fn func(interface{}) int) Option {
+	return func(c Config) {
+		if sc, ok := c.(SizeFuncConfig); ok {
+			sc.SetSizeFunc(fn)
+		}
+	}
+}
+
+// WithSizer returns an Option that makes pool to verify size of objects
+// implementing Sizer interface passed to Put().
+func WithSizer() Option {
+	return WithSizeFunc(sizerSize)
+}
+
+func sizerSize(x interface{}) int {
+	if s, ok := x.(Sizer); ok {
+		return s.Size()
+	}
+	return -1
+}
+
+// WithReset returns an Option that makes pool to reset objects by given
+// function when they are taken for future reuse by Put(). It overrides
+// previous WithReset() or WithResetter() option.
+func WithReset(fn func(interface{})) Option {
+	return func(c Config) {
+		if rc, ok := c.(ResetConfig); ok {
+			rc.SetReset(fn)
+		}
+	}
+}
+
+// WithResetter returns an Option that makes pool to reset objects
+// implementing Resetter interface when they are taken for future reuse by
+// Put(). It overrides previous WithReset() or WithResetter() option.
+func WithResetter() Option {
+	return WithReset(resetterReset)
+}
+
+func resetterReset(x interface{}) {
+	if r, ok := x.(Resetter); ok {
+		r.Reset()
+	}
+}
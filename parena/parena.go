@@ -0,0 +1,160 @@
+// Package parena contains tools for returning pooled objects back to their
+// pools at once.
+//
+// Quick example:
+//
+//   func handler(w http.ResponseWriter, r *http.Request) {
+//       a := parena.New()
+//       defer a.Release()
+//
+//       buf := a.Bytes(nil, 0, 512)
+//       bw := a.Writer(nil, w, 4096)
+//
+//       // Work with buf and bw without care of returning them to the pools.
+//   }
+//
+package parena
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"sync"
+
+	"github.com/gobwas/pool"
+	"github.com/gobwas/pool/pbufio"
+	"github.com/gobwas/pool/pbytes"
+)
+
+type contextKey struct{}
+
+// NewContext returns a new Context that carries given Arena.
+func NewContext(ctx context.Context, a *Arena) context.Context {
+	return context.WithValue(ctx, contextKey{}, a)
+}
+
+// FromContext returns the Arena stored in ctx, if any.
+func FromContext(ctx context.Context) *Arena {
+	a, _ := ctx.Value(contextKey{}).(*Arena)
+	return a
+}
+
+// Arena records objects pulled from the pools through it and returns all of
+// them back at once on Release() call.
+//
+// Arena is safe for concurrent use. Zero value of Arena is ready to use.
+type Arena struct {
+	mu      sync.Mutex
+	release []func()
+}
+
+// New creates new Arena.
+func New() *Arena {
+	return new(Arena)
+}
+
+// Scope creates nested Arena which is released when a is released. Nested
+// Arena could also be released on its own before that.
+func (a *Arena) Scope() *Arena {
+	s := New()
+	a.Defer(s.Release)
+	return s
+}
+
+// Defer registers fn to be called on Release(). Functions are called in
+// reverse order of their registration.
+func (a *Arena) Defer(fn func()) {
+	a.mu.Lock()
+	a.release = append(a.release, fn)
+	a.mu.Unlock()
+}
+
+// Release returns all objects pulled through a back to their pools and calls
+// all functions registered by Defer(). After Release() returns a could be
+// reused.
+//
+// If some of the registered functions panics, the rest of them is still
+// called before panic goes further. That is, it is safe to call Release()
+// deferred in code which could panic.
+func (a *Arena) Release() {
+	a.mu.Lock()
+	fns := a.release
+	a.release = nil
+	a.mu.Unlock()
+
+	release(fns)
+}
+
+// Get pulls object whose generic size is at least of given size from p. If p
+// has no object to reuse, alloc is called with real size of object to be
+// created.
+// If p is nil, pool.Default() is used.
+func (a *Arena) Get(p *pool.Pool, size int, alloc func(int) interface{}) interface{} {
+	if p == nil {
+		p = pool.Default()
+	}
+	x, n := p.Get(size)
+	if x == nil {
+		x = alloc(n)
+	}
+	a.Defer(func() {
+		p.Put(x, n)
+	})
+	return x
+}
+
+// Bytes returns probably reused slice of bytes from p with at least capacity
+// of c and exactly len of n.
+// If p is nil, pbytes.Default() is used.
+func (a *Arena) Bytes(p *pbytes.Pool, n, c int) []byte {
+	if p == nil {
+		p = pbytes.Default()
+	}
+	bts := p.Get(n, c)
+	a.Defer(func() {
+		p.Put(bts)
+	})
+	return bts
+}
+
+// Reader returns bufio.Reader from p whose buffer has at least size bytes.
+// If p is nil, pbufio.DefaultReader() is used.
+func (a *Arena) Reader(p *pbufio.ReaderPool, r io.Reader, size int) *bufio.Reader {
+	if p == nil {
+		p = pbufio.DefaultReader()
+	}
+	br := p.Get(r, size)
+	a.Defer(func() {
+		p.Put(br)
+	})
+	return br
+}
+
+// Writer returns bufio.Writer from p whose buffer has at least size bytes.
+// Note that writer is not flushed on Release().
+// If p is nil, pbufio.DefaultWriter() is used.
+func (a *Arena) Writer(p *pbufio.WriterPool, w io.Writer, size int) *bufio.Writer {
+	if p == nil {
+		p = pbufio.DefaultWriter()
+	}
+	bw := p.Get(w, size)
+	a.Defer(func() {
+		p.Put(bw)
+	})
+	return bw
+}
+
+// release calls fns in reverse order. If some fn panics, the rest of fns is
+// called in deferred call before the panic goes further.
+func release(fns []func()) {
+	defer func() {
+		if len(fns) > 0 {
+			release(fns)
+		}
+	}()
+	for len(fns) > 0 {
+		fn := fns[len(fns)-1]
+		fns = fns[:len(fns)-1]
+		fn()
+	}
+}
@@ -0,0 +1,32 @@
+//go:build !pool_sanitize
+// +build !pool_sanitize
+
+package parena
+
+import (
+	"testing"
+
+	"github.com/gobwas/pool"
+	"github.com/gobwas/pool/pbytes"
+)
+
+func TestArenaBytesRelease(t *testing.T) {
+	var c pool.Counter
+	p := pbytes.Custom(
+		pool.WithLogSizeMapping(),
+		pool.WithLogSizeRange(0, 64),
+		pool.WithObserver(&c),
+	)
+	a := New()
+	bts := a.Bytes(p, 10, 16)
+	if len(bts) != 10 || cap(bts) != 16 {
+		t.Fatalf("Bytes() returned %d-len %d-cap slice; want 10-len 16-cap", len(bts), cap(bts))
+	}
+	if s := c.Stats(); s.Puts != 0 {
+		t.Fatalf("slice was returned to the pool before Release()")
+	}
+	a.Release()
+	if s := c.Stats(); s.Puts != 1 || s.PutBytes != 16 {
+		t.Errorf("slice was not returned to the pool; stats are %+v", s)
+	}
+}
@@ -0,0 +1,98 @@
+package parena
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/gobwas/pool"
+	"github.com/gobwas/pool/pbytes"
+)
+
+func TestArenaRelease(t *testing.T) {
+	var act []int
+	a := New()
+	for i := 0; i < 3; i++ {
+		i := i
+		a.Defer(func() { act = append(act, i) })
+	}
+	s := a.Scope()
+	s.Defer(func() { act = append(act, 42) })
+
+	a.Release()
+	if exp := []int{42, 2, 1, 0}; !reflect.DeepEqual(act, exp) {
+		t.Errorf("unexpected release order: %v; want %v", act, exp)
+	}
+
+	// Subsequent Release() calls must not call anything.
+	act = act[:0]
+	a.Release()
+	s.Release()
+	if len(act) != 0 {
+		t.Errorf("unexpected calls on repeated Release(): %v", act)
+	}
+}
+
+func TestArenaReleasePanic(t *testing.T) {
+	var released int
+	a := New()
+	a.Defer(func() { released++ })
+	a.Defer(func() { panic("boom") })
+	a.Defer(func() { released++ })
+
+	func() {
+		defer func() {
+			if err := recover(); err != "boom" {
+				t.Errorf("unexpected panic: %v; want %q", err, "boom")
+			}
+		}()
+		a.Release()
+	}()
+	if released != 2 {
+		t.Errorf("released %d objects; want %d", released, 2)
+	}
+}
+
+func TestArenaBytes(t *testing.T) {
+	p := pbytes.New(0, 64)
+	a := New()
+	bts := a.Bytes(p, 10, 16)
+	if len(bts) != 10 || cap(bts) < 16 {
+		t.Fatalf("Bytes() returned %d-len %d-cap slice; want 10-len at least 16-cap", len(bts), cap(bts))
+	}
+	a.Release()
+}
+
+func TestArenaGet(t *testing.T) {
+	var c pool.Counter
+	p := pool.Custom(
+		pool.WithLogSizeMapping(),
+		pool.WithLogSizeRange(0, 64),
+		pool.WithObserver(&c),
+	)
+	a := New()
+	a.Get(p, 10, func(n int) interface{} {
+		if n != 16 {
+			t.Errorf("alloc called with %d size; want %d", n, 16)
+		}
+		return new([16]byte)
+	})
+	if s := c.Stats(); s.Puts != 0 {
+		t.Fatalf("object was returned to the pool before Release()")
+	}
+	a.Release()
+	if s := c.Stats(); s.Puts != 1 || s.PutBytes != 16 {
+		t.Errorf("object was not returned to the pool; stats are %+v", s)
+	}
+}
+
+func TestContext(t *testing.T) {
+	ctx := context.Background()
+	if FromContext(ctx) != nil {
+		t.Fatalf("unexpected Arena in empty context")
+	}
+	a := New()
+	if FromContext(NewContext(ctx, a)) != a {
+		t.Fatalf("FromContext() returned unexpected Arena")
+	}
+}
@@ -0,0 +1,81 @@
+package pbufio
+
+import (
+	"bufio"
+	"io"
+
+	"github.com/gobwas/pool"
+)
+
+// AcquireWriter returns Handle of bufio.Writer whose buffer has at least size
+// bytes.
+// AcquireWriter is a wrapper around DefaultWriter().Acquire().
+func AcquireWriter(w io.Writer, size int) WriterHandle { return DefaultWriter().Acquire(w, size) }
+
+// AcquireReader returns Handle of bufio.Reader whose buffer has at least size
+// bytes.
+// AcquireReader is a wrapper around DefaultReader().Acquire().
+func AcquireReader(r io.Reader, size int) ReaderHandle { return DefaultReader().Acquire(r, size) }
+
+// Acquire returns Handle of bufio.Writer whose buffer has at least size bytes.
+// It could be returned to the pool by Handle's Release() call.
+func (wp *WriterPool) Acquire(w io.Writer, size int) WriterHandle {
+	h := wp.pool.Acquire(size)
+	if v := h.Value(); v != nil {
+		v.(*bufio.Writer).Reset(w)
+	} else {
+		h.Set(bufio.NewWriterSize(w, h.Size()))
+	}
+	return WriterHandle{wp, h}
+}
+
+// Acquire returns Handle of bufio.Reader whose buffer has at least size bytes.
+// It could be returned to the pool by Handle's Release() call.
+func (rp *ReaderPool) Acquire(r io.Reader, size int) ReaderHandle {
+	h := rp.pool.Acquire(size)
+	if v := h.Value(); v != nil {
+		v.(*bufio.Reader).Reset(r)
+	} else {
+		h.Set(bufio.NewReaderSize(r, h.Size()))
+	}
+	return ReaderHandle{h}
+}
+
+// WriterHandle binds bufio.Writer to the pool it was taken from.
+type WriterHandle struct {
+	wp *WriterPool
+	h  pool.Handle
+}
+
+// Writer returns bufio.Writer bound to the handle. It returns nil after
+// Release().
+func (h WriterHandle) Writer() *bufio.Writer {
+	bw, _ := h.h.Value().(*bufio.Writer)
+	return bw
+}
+
+// Release returns bufio.Writer to the pool it was taken from. It panics if
+// handle is released more than once. Unflushed data is treated the same way
+// as WriterPool.Put() does.
+func (h WriterHandle) Release() {
+	h.wp.checkUnflushed(h.Writer())
+	h.h.Release()
+}
+
+// ReaderHandle binds bufio.Reader to the pool it was taken from.
+type ReaderHandle struct {
+	h pool.Handle
+}
+
+// Reader returns bufio.Reader bound to the handle. It returns nil after
+// Release().
+func (h ReaderHandle) Reader() *bufio.Reader {
+	br, _ := h.h.Value().(*bufio.Reader)
+	return br
+}
+
+// Release returns bufio.Reader to the pool it was taken from. It panics if
+// handle is released more than once.
+func (h ReaderHandle) Release() {
+	h.h.Release()
+}
@@ -0,0 +1,253 @@
+package pbufio
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+
+	"github.com/gobwas/pool/pbytes"
+)
+
+const (
+	minReadBufferSize        = 16
+	maxConsecutiveEmptyReads = 100
+)
+
+var errNegativeRead = errors.New("pbufio: reader returned negative count from Read")
+
+// LazyReader implements buffering for an io.Reader object just like
+// bufio.Reader does. Unlike bufio.Reader, it takes its buffer from the
+// pbytes.Pool only when data is read from the underlying io.Reader and
+// returns it back as soon as all buffered data is consumed. That is, idle
+// LazyReader does not hold any buffer.
+//
+// Note that bytes returned by Peek() and ReadSlice() are valid until the next
+// read call; so the buffer is held until that call even if it is drained.
+//
+// Note that the buffer is taken before reading from the underlying io.Reader,
+// so it is held while that read blocks. That is, LazyReader saves memory of
+// idle connections only if it is read when data is available (e.g. after
+// netpoll-like readiness notification), but not if a goroutine is parked in
+// Read() waiting for the data.
+type LazyReader struct {
+	rd   io.Reader
+	pool *pbytes.Pool
+	size int
+	buf  []byte
+	r, w int
+	err  error
+}
+
+// NewLazyReader returns LazyReader whose buffer has at least size bytes and
+// is taken from pbytes.Default().
+func NewLazyReader(rd io.Reader, size int) *LazyReader {
+	return NewLazyReaderPool(rd, size, pbytes.Default())
+}
+
+// NewLazyReaderPool returns LazyReader whose buffer has at least size bytes
+// and is taken from given pool.
+func NewLazyReaderPool(rd io.Reader, size int, p *pbytes.Pool) *LazyReader {
+	if size < minReadBufferSize {
+		size = minReadBufferSize
+	}
+	return &LazyReader{
+		rd:   rd,
+		pool: p,
+		size: size,
+	}
+}
+
+// Size returns the size of the underlying buffer in bytes.
+func (b *LazyReader) Size() int { return b.size }
+
+// Buffered returns the number of bytes that can be read from the current
+// buffer.
+func (b *LazyReader) Buffered() int { return b.w - b.r }
+
+// Reset discards any buffered data, returns the buffer to the pool, resets
+// all state, and switches the buffered reader to read from rd.
+func (b *LazyReader) Reset(rd io.Reader) {
+	if b.buf != nil {
+		b.pool.Put(b.buf)
+	}
+	*b = LazyReader{
+		rd:   rd,
+		pool: b.pool,
+		size: b.size,
+	}
+}
+
+// Read reads data into p. It returns the number of bytes read into p. The
+// bytes are taken from at most one Read on the underlying io.Reader, hence n
+// may be less than len(p).
+func (b *LazyReader) Read(p []byte) (n int, err error) {
+	if len(p) == 0 {
+		if b.Buffered() > 0 {
+			return 0, nil
+		}
+		return 0, b.readErr()
+	}
+	if b.r == b.w {
+		if b.err != nil {
+			b.release()
+			return 0, b.readErr()
+		}
+		if len(p) >= b.size {
+			// Large read, empty buffer.
+			// Read directly into p to avoid copy.
+			b.release()
+			n, b.err = b.rd.Read(p)
+			if n < 0 {
+				panic(errNegativeRead)
+			}
+			return n, b.readErr()
+		}
+		b.acquire()
+		b.r, b.w = 0, 0
+		n, b.err = b.rd.Read(b.buf)
+		if n < 0 {
+			panic(errNegativeRead)
+		}
+		if n == 0 {
+			b.release()
+			return 0, b.readErr()
+		}
+		b.w += n
+	}
+
+	n = copy(p, b.buf[b.r:b.w])
+	b.r += n
+	b.release()
+	return n, nil
+}
+
+// ReadByte reads and returns a single byte. If no byte is available, returns
+// an error.
+func (b *LazyReader) ReadByte() (byte, error) {
+	for b.r == b.w {
+		if b.err != nil {
+			b.release()
+			return 0, b.readErr()
+		}
+		b.fill()
+	}
+	c := b.buf[b.r]
+	b.r++
+	b.release()
+	return c, nil
+}
+
+// Peek returns the next n bytes without advancing the reader. If Peek returns
+// fewer than n bytes, it also returns an error explaining why the read is
+// short. The error is bufio.ErrBufferFull if n is larger than b's buffer
+// size.
+func (b *LazyReader) Peek(n int) ([]byte, error) {
+	if n < 0 {
+		return nil, bufio.ErrNegativeCount
+	}
+	for b.w-b.r < n && b.w-b.r < b.size && b.err == nil {
+		b.fill()
+	}
+	if n > b.size {
+		return b.buf[b.r:b.w], bufio.ErrBufferFull
+	}
+	var err error
+	if avail := b.w - b.r; avail < n {
+		n = avail
+		err = b.readErr()
+		if err == nil {
+			err = bufio.ErrBufferFull
+		}
+	}
+	if n == 0 {
+		b.release()
+		return nil, err
+	}
+	return b.buf[b.r : b.r+n], err
+}
+
+// ReadSlice reads until the first occurrence of delim in the input, returning
+// a slice pointing at the bytes in the buffer. The bytes stop being valid at
+// the next read. It behaves just like bufio.Reader.ReadSlice() does.
+func (b *LazyReader) ReadSlice(delim byte) (line []byte, err error) {
+	var s int // Search start index.
+	for {
+		if i := bytes.IndexByte(b.buf[b.r+s:b.w], delim); i >= 0 {
+			i += s
+			line = b.buf[b.r : b.r+i+1]
+			b.r += i + 1
+			break
+		}
+		if b.err != nil {
+			line = b.buf[b.r:b.w]
+			b.r = b.w
+			err = b.readErr()
+			break
+		}
+		if b.Buffered() >= b.size {
+			line = b.buf[b.r:b.w]
+			b.r = b.w
+			err = bufio.ErrBufferFull
+			break
+		}
+		s = b.w - b.r
+		b.fill()
+	}
+	if len(line) == 0 {
+		b.release()
+		line = nil
+	}
+	return line, err
+}
+
+// fill reads a new chunk into the buffer, taking the buffer from the pool if
+// necessary.
+func (b *LazyReader) fill() {
+	b.acquire()
+	// Slide existing data to beginning.
+	if b.r > 0 {
+		copy(b.buf, b.buf[b.r:b.w])
+		b.w -= b.r
+		b.r = 0
+	}
+	if b.w >= len(b.buf) {
+		panic("pbufio: tried to fill full buffer")
+	}
+	for i := maxConsecutiveEmptyReads; i > 0; i-- {
+		n, err := b.rd.Read(b.buf[b.w:])
+		if n < 0 {
+			panic(errNegativeRead)
+		}
+		b.w += n
+		if err != nil {
+			b.err = err
+			return
+		}
+		if n > 0 {
+			return
+		}
+	}
+	b.err = io.ErrNoProgress
+}
+
+func (b *LazyReader) readErr() error {
+	err := b.err
+	b.err = nil
+	return err
+}
+
+func (b *LazyReader) acquire() {
+	if b.buf == nil {
+		b.buf = b.pool.GetLen(b.size)
+	}
+}
+
+// release returns the buffer to the pool if there is no unread data in it.
+func (b *LazyReader) release() {
+	if b.buf != nil && b.r == b.w {
+		b.pool.Put(b.buf)
+		b.buf = nil
+		b.r, b.w = 0, 0
+	}
+}
@@ -0,0 +1,153 @@
+package pbufio
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/gobwas/pool/pbytes"
+)
+
+func TestLazyReaderRead(t *testing.T) {
+	data := strings.Repeat("hello, world\n", 100)
+	for _, test := range []struct {
+		name string
+		wrap func(io.Reader) io.Reader
+	}{
+		{"plain", func(r io.Reader) io.Reader { return r }},
+		{"one byte", iotest.OneByteReader},
+		{"half", iotest.HalfReader},
+		{"data err", iotest.DataErrReader},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			for _, size := range []int{1, 16, 64, 4096} {
+				br := NewLazyReaderPool(test.wrap(strings.NewReader(data)), size, pbytes.New(0, 4096))
+				bts, err := ioutil.ReadAll(br)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(bts) != data {
+					t.Fatalf("unexpected data read with %d-size buffer", size)
+				}
+				if br.buf != nil {
+					t.Errorf("drained reader holds buffer")
+				}
+			}
+		})
+	}
+}
+
+func TestLazyReaderReadByte(t *testing.T) {
+	br := NewLazyReader(iotest.HalfReader(strings.NewReader("abc")), 16)
+	var act []byte
+	for {
+		c, err := br.ReadByte()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		act = append(act, c)
+		if br.Buffered() == 0 && br.buf != nil {
+			t.Fatalf("drained reader holds buffer")
+		}
+	}
+	if string(act) != "abc" {
+		t.Errorf("ReadByte() read %q; want %q", act, "abc")
+	}
+}
+
+func TestLazyReaderPeek(t *testing.T) {
+	data := strings.Repeat("hello", 4)
+	br := NewLazyReader(iotest.OneByteReader(strings.NewReader(data)), 16)
+	if p, err := br.Peek(3); err != nil || string(p) != "hel" {
+		t.Fatalf("Peek(3) = %q, %v; want %q, nil", p, err, "hel")
+	}
+	if _, err := br.Peek(17); err != bufio.ErrBufferFull {
+		t.Fatalf("Peek(17) error is %v; want %v", err, bufio.ErrBufferFull)
+	}
+	if p, err := br.Peek(-1); err != bufio.ErrNegativeCount {
+		t.Fatalf("Peek(-1) = %q, %v; want %v", p, err, bufio.ErrNegativeCount)
+	}
+	bts, err := ioutil.ReadAll(br)
+	if err != nil || string(bts) != data {
+		t.Fatalf("ReadAll() = %q, %v; want %q, nil", bts, err, data)
+	}
+	if p, err := br.Peek(1); err != io.EOF || len(p) != 0 {
+		t.Fatalf("Peek(1) = %q, %v; want empty, %v", p, err, io.EOF)
+	}
+	if br.buf != nil {
+		t.Errorf("drained reader holds buffer")
+	}
+}
+
+func TestLazyReaderReadSlice(t *testing.T) {
+	data := "foo\nbar\n" + strings.Repeat("baz", 10) + "\nqux"
+	// Note that bufio.Reader does not allow buffers smaller than 16 bytes.
+	for _, size := range []int{16, 32, 64} {
+		var (
+			exp = bufio.NewReaderSize(strings.NewReader(data), size)
+			act = NewLazyReader(strings.NewReader(data), size)
+		)
+		for {
+			expLine, expErr := exp.ReadSlice('\n')
+			actLine, actErr := act.ReadSlice('\n')
+			if !bytes.Equal(actLine, expLine) || actErr != expErr {
+				t.Fatalf(
+					"ReadSlice() = %q, %v; want %q, %v",
+					actLine, actErr, expLine, expErr,
+				)
+			}
+			if expErr == io.EOF {
+				break
+			}
+		}
+		// Last returned line is still valid, so buffer must be released on
+		// the next read call.
+		if act.buf == nil {
+			t.Errorf("reader released buffer before the next read")
+		}
+		if _, err := act.ReadSlice('\n'); err != io.EOF {
+			t.Errorf("ReadSlice() after EOF returned %v; want %v", err, io.EOF)
+		}
+		if act.buf != nil {
+			t.Errorf("drained reader holds buffer")
+		}
+	}
+}
+
+func TestLazyReaderReset(t *testing.T) {
+	br := NewLazyReader(strings.NewReader("foo"), 16)
+	if _, err := br.Peek(1); err != nil {
+		t.Fatal(err)
+	}
+	br.Reset(strings.NewReader("bar"))
+	if br.buf != nil || br.Buffered() != 0 {
+		t.Fatalf("reset reader holds buffered data")
+	}
+	bts, err := ioutil.ReadAll(br)
+	if err != nil || string(bts) != "bar" {
+		t.Fatalf("ReadAll() = %q, %v; want %q, nil", bts, err, "bar")
+	}
+}
+
+func TestLazyReaderSmallSize(t *testing.T) {
+	for _, size := range []int{-1, 0, 1} {
+		br := NewLazyReader(strings.NewReader("hello"), size)
+		if n := br.Size(); n != minReadBufferSize {
+			t.Errorf("Size() = %d; want %d", n, minReadBufferSize)
+		}
+		bts, err := ioutil.ReadAll(iotest.OneByteReader(br))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if act := string(bts); act != "hello" {
+			t.Errorf("read %q; want %q", act, "hello")
+		}
+	}
+}
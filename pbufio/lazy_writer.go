@@ -0,0 +1,257 @@
+package pbufio
+
+import (
+	"io"
+
+	"github.com/gobwas/pool/pbytes"
+)
+
+const defaultBufSize = 4096
+
+// LazyWriter implements buffering for an io.Writer object just like
+// bufio.Writer does. Unlike bufio.Writer, it takes its buffer from the
+// pbytes.Pool on first write and returns it back right after successful
+// Flush(). That is, idle LazyWriter does not hold any buffer.
+//
+// If an error occurs writing to a LazyWriter, no more data will be accepted
+// and all subsequent writes, and Flush(), will return the error.
+type LazyWriter struct {
+	wr   io.Writer
+	pool *pbytes.Pool
+	size int
+	buf  []byte
+	n    int
+	err  error
+}
+
+// NewLazyWriter returns LazyWriter whose buffer has at least size bytes and
+// is taken from pbytes.Default().
+func NewLazyWriter(wr io.Writer, size int) *LazyWriter {
+	return NewLazyWriterPool(wr, size, pbytes.Default())
+}
+
+// NewLazyWriterPool returns LazyWriter whose buffer has at least size bytes
+// and is taken from given pool. If size is not positive, default size of
+// bufio.Writer is used.
+func NewLazyWriterPool(wr io.Writer, size int, p *pbytes.Pool) *LazyWriter {
+	if size <= 0 {
+		size = defaultBufSize
+	}
+	return &LazyWriter{
+		wr:   wr,
+		pool: p,
+		size: size,
+	}
+}
+
+// Size returns the size of the underlying buffer in bytes.
+func (b *LazyWriter) Size() int { return b.size }
+
+// Available returns how many bytes are unused in the buffer.
+func (b *LazyWriter) Available() int { return b.size - b.n }
+
+// Buffered returns the number of bytes that have been written into the
+// current buffer.
+func (b *LazyWriter) Buffered() int { return b.n }
+
+// Reset discards any unflushed buffered data, returns the buffer to the pool,
+// clears any error, and resets b to write its output to wr.
+func (b *LazyWriter) Reset(wr io.Writer) {
+	if b.buf != nil {
+		b.pool.Put(b.buf)
+	}
+	*b = LazyWriter{
+		wr:   wr,
+		pool: b.pool,
+		size: b.size,
+	}
+}
+
+// Flush writes any buffered data to the underlying io.Writer and returns the
+// buffer to the pool.
+func (b *LazyWriter) Flush() error {
+	if err := b.flush(); err != nil {
+		return err
+	}
+	b.release()
+	return nil
+}
+
+// Write writes the contents of p into the buffer. It returns the number of
+// bytes written. If nn < len(p), it also returns an error explaining why the
+// write is short.
+func (b *LazyWriter) Write(p []byte) (nn int, err error) {
+	for len(p) > b.Available() && b.err == nil {
+		var n int
+		if b.Buffered() == 0 {
+			// Large write, empty buffer.
+			// Write directly from p to avoid copy.
+			n, b.err = b.wr.Write(p)
+		} else {
+			n = copy(b.buf[b.n:], p)
+			b.n += n
+			b.flush()
+		}
+		nn += n
+		p = p[n:]
+	}
+	if b.err != nil {
+		return nn, b.err
+	}
+	if len(p) == 0 {
+		// Buffer could be flushed before direct write.
+		b.release()
+		return nn, nil
+	}
+	b.acquire()
+	n := copy(b.buf[b.n:], p)
+	b.n += n
+	nn += n
+	return nn, nil
+}
+
+// WriteString writes a string. It returns the number of bytes written. If the
+// count is less than len(s), it also returns an error explaining why the
+// write is short.
+func (b *LazyWriter) WriteString(s string) (nn int, err error) {
+	for len(s) > b.Available() && b.err == nil {
+		if b.Buffered() == 0 {
+			// Large write, empty buffer.
+			// Write directly from s if underlying writer supports it.
+			if sw, ok := b.wr.(stringWriter); ok {
+				var n int
+				n, b.err = sw.WriteString(s)
+				nn += n
+				s = s[n:]
+				continue
+			}
+		}
+		b.acquire()
+		n := copy(b.buf[b.n:], s)
+		b.n += n
+		nn += n
+		s = s[n:]
+		b.flush()
+	}
+	if b.err != nil {
+		return nn, b.err
+	}
+	if len(s) == 0 {
+		// Buffer could be flushed before direct write.
+		b.release()
+		return nn, nil
+	}
+	b.acquire()
+	n := copy(b.buf[b.n:], s)
+	b.n += n
+	nn += n
+	return nn, nil
+}
+
+// WriteByte writes a single byte.
+func (b *LazyWriter) WriteByte(c byte) error {
+	if b.err != nil {
+		return b.err
+	}
+	if b.Available() <= 0 && b.flush() != nil {
+		return b.err
+	}
+	b.acquire()
+	b.buf[b.n] = c
+	b.n++
+	return nil
+}
+
+// ReadFrom implements io.ReaderFrom. If the underlying writer supports the
+// ReadFrom method, and b has no buffered data yet, this calls the underlying
+// ReadFrom without taking the buffer.
+func (b *LazyWriter) ReadFrom(r io.Reader) (n int64, err error) {
+	if b.err != nil {
+		return 0, b.err
+	}
+	if b.Buffered() == 0 {
+		if w, ok := b.wr.(io.ReaderFrom); ok {
+			n, err = w.ReadFrom(r)
+			b.err = err
+			return n, err
+		}
+	}
+	b.acquire()
+	var m int
+	for {
+		if b.Available() == 0 {
+			if err1 := b.flush(); err1 != nil {
+				return n, err1
+			}
+		}
+		nr := 0
+		for nr < maxConsecutiveEmptyReads {
+			m, err = r.Read(b.buf[b.n:])
+			if m != 0 || err != nil {
+				break
+			}
+			nr++
+		}
+		if nr == maxConsecutiveEmptyReads {
+			return n, io.ErrNoProgress
+		}
+		b.n += m
+		n += int64(m)
+		if err != nil {
+			break
+		}
+	}
+	if err == io.EOF {
+		// If we filled the buffer exactly, flush preemptively.
+		if b.Available() == 0 {
+			err = b.flush()
+		} else {
+			err = nil
+		}
+	}
+	b.release()
+	return n, err
+}
+
+// flush writes any buffered data to the underlying io.Writer. Unlike Flush()
+// it does not return the buffer to the pool.
+func (b *LazyWriter) flush() error {
+	if b.err != nil {
+		return b.err
+	}
+	if b.n == 0 {
+		return nil
+	}
+	n, err := b.wr.Write(b.buf[0:b.n])
+	if n < b.n && err == nil {
+		err = io.ErrShortWrite
+	}
+	if err != nil {
+		if n > 0 && n < b.n {
+			copy(b.buf[0:b.n-n], b.buf[n:b.n])
+		}
+		b.n -= n
+		b.err = err
+		return err
+	}
+	b.n = 0
+	return nil
+}
+
+type stringWriter interface {
+	WriteString(string) (int, error)
+}
+
+func (b *LazyWriter) acquire() {
+	if b.buf == nil {
+		b.buf = b.pool.GetLen(b.size)
+	}
+}
+
+// release returns the buffer to the pool if there is no buffered data in it.
+func (b *LazyWriter) release() {
+	if b.buf != nil && b.n == 0 {
+		b.pool.Put(b.buf)
+		b.buf = nil
+	}
+}
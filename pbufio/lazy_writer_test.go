@@ -0,0 +1,175 @@
+package pbufio
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/gobwas/pool/pbytes"
+)
+
+func TestLazyWriterWrite(t *testing.T) {
+	data := strings.Repeat("hello, world\n", 100)
+	for _, test := range []struct {
+		name  string
+		write func(*LazyWriter, string) error
+	}{
+		{
+			name: "Write",
+			write: func(bw *LazyWriter, s string) error {
+				_, err := bw.Write([]byte(s))
+				return err
+			},
+		},
+		{
+			name: "WriteString",
+			write: func(bw *LazyWriter, s string) error {
+				_, err := bw.WriteString(s)
+				return err
+			},
+		},
+		{
+			name: "WriteByte",
+			write: func(bw *LazyWriter, s string) error {
+				for i := 0; i < len(s); i++ {
+					if err := bw.WriteByte(s[i]); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			name: "ReadFrom",
+			write: func(bw *LazyWriter, s string) error {
+				_, err := bw.ReadFrom(iotest.HalfReader(strings.NewReader(s)))
+				return err
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			for _, size := range []int{1, 16, 64, 4096} {
+				var buf bytes.Buffer
+				bw := NewLazyWriterPool(
+					struct{ io.Writer }{&buf}, // Hide buf's methods.
+					size, pbytes.New(0, 4096),
+				)
+				for _, chunk := range []string{data[:7], data[7:100], data[100:]} {
+					if err := test.write(bw, chunk); err != nil {
+						t.Fatal(err)
+					}
+				}
+				if err := bw.Flush(); err != nil {
+					t.Fatal(err)
+				}
+				if buf.String() != data {
+					t.Fatalf("unexpected data written with %d-size buffer", size)
+				}
+				if bw.buf != nil {
+					t.Errorf("flushed writer holds buffer")
+				}
+			}
+		})
+	}
+}
+
+func TestLazyWriterDirectWrite(t *testing.T) {
+	data := strings.Repeat("x", 40)
+	for _, test := range []struct {
+		name  string
+		write func(*LazyWriter, string) error
+	}{
+		{
+			name: "Write",
+			write: func(bw *LazyWriter, s string) error {
+				_, err := bw.Write([]byte(s))
+				return err
+			},
+		},
+		{
+			name: "WriteString",
+			write: func(bw *LazyWriter, s string) error {
+				_, err := bw.WriteString(s)
+				return err
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			bw := NewLazyWriter(&buf, 16)
+			if err := test.write(bw, "hello"); err != nil {
+				t.Fatal(err)
+			}
+			// Fill and flush the buffer, then write the rest directly.
+			if err := test.write(bw, data); err != nil {
+				t.Fatal(err)
+			}
+			if act, exp := buf.String(), "hello"+data; act != exp {
+				t.Fatalf("unexpected data written: %q; want %q", act, exp)
+			}
+			if bw.buf != nil {
+				t.Errorf("writer holds buffer after direct write")
+			}
+		})
+	}
+}
+
+func TestLazyWriterBuffered(t *testing.T) {
+	var buf bytes.Buffer
+	bw := NewLazyWriter(&buf, 16)
+	if n := bw.Available(); n != 16 {
+		t.Errorf("Available() = %d; want %d", n, 16)
+	}
+	bw.WriteString("hello")
+	if n := bw.Buffered(); n != 5 {
+		t.Errorf("Buffered() = %d; want %d", n, 5)
+	}
+	if n := bw.Available(); n != 11 {
+		t.Errorf("Available() = %d; want %d", n, 11)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("unexpected write to the underlying writer")
+	}
+	if bw.buf == nil {
+		t.Errorf("writer does not hold buffer with unflushed data")
+	}
+	bw.Reset(&buf)
+	if bw.buf != nil || bw.Buffered() != 0 {
+		t.Errorf("reset writer holds buffered data")
+	}
+}
+
+func TestLazyWriterError(t *testing.T) {
+	bw := NewLazyWriter(errWriter{}, 16)
+	if _, err := bw.WriteString("hello"); err != nil {
+		t.Fatal(err)
+	}
+	if err := bw.Flush(); err != errWrite {
+		t.Fatalf("Flush() = %v; want %v", err, errWrite)
+	}
+	if _, err := bw.WriteString("world"); err != errWrite {
+		t.Fatalf("WriteString() after error = %v; want %v", err, errWrite)
+	}
+	if n := bw.Buffered(); n != 5 {
+		t.Errorf("Buffered() = %d; want %d", n, 5)
+	}
+}
+
+func TestLazyWriterZeroSize(t *testing.T) {
+	var buf bytes.Buffer
+	bw := NewLazyWriter(&buf, 0)
+	if n := bw.Size(); n != defaultBufSize {
+		t.Errorf("Size() = %d; want %d", n, defaultBufSize)
+	}
+	if err := bw.WriteByte('a'); err != nil {
+		t.Fatal(err)
+	}
+	if err := bw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if act := buf.String(); act != "a" {
+		t.Errorf("written %q; want %q", act, "a")
+	}
+}
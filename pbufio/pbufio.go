@@ -1,54 +1,148 @@
-// Package pbufio contains tools for pooling bufio.Reader and bufio.Writers.
+// Package pbufio contains tools for pooling bufio.Reader, bufio.Writers and
+// bufio.Scanners.
 package pbufio
 
 import (
 	"bufio"
+	"bytes"
 	"io"
+	"sync"
+	"sync/atomic"
 
 	"github.com/gobwas/pool"
 )
 
+// Default pools are the initial pools used by package level functions.
+// They are registered by "pbufio.DefaultWriterPool" and
+// "pbufio.DefaultReaderPool" names and could be configured by pool.ConfigEnv
+// environment variable. Their events could be counted by pool.Counter enabled
+// by pool.Settings.Stats.
+//
+// Note that assigning to default pools does not change the pools used by
+// package level functions; use SetDefaultWriter() and SetDefaultReader()
+// instead.
 var (
-	DefaultWriterPool = NewWriterPool(256, 65536)
-	DefaultReaderPool = NewReaderPool(256, 65536)
+	DefaultWriterPool = CustomWriterPool(pool.ConfigOptions("pbufio.DefaultWriterPool",
+		pool.WithLogSizeMapping(),
+		pool.WithLogSizeRange(256, 65536),
+	)...)
+	DefaultReaderPool = CustomReaderPool(pool.ConfigOptions("pbufio.DefaultReaderPool",
+		pool.WithLogSizeMapping(),
+		pool.WithLogSizeRange(256, 65536),
+	)...)
 )
 
+var current struct {
+	mu     sync.Mutex
+	writer atomic.Value
+	reader atomic.Value
+}
+
+func init() {
+	current.writer.Store(DefaultWriterPool)
+	current.reader.Store(DefaultReaderPool)
+}
+
+// DefaultWriter returns the WriterPool currently used by package level
+// functions.
+func DefaultWriter() *WriterPool {
+	return current.writer.Load().(*WriterPool)
+}
+
+// DefaultReader returns the ReaderPool currently used by package level
+// functions.
+func DefaultReader() *ReaderPool {
+	return current.reader.Load().(*ReaderPool)
+}
+
+// SetDefaultWriter makes wp the WriterPool used by package level functions
+// and returns the previous one. It is safe to call SetDefaultWriter
+// concurrently with package level functions. It panics if wp is nil.
+func SetDefaultWriter(wp *WriterPool) (prev *WriterPool) {
+	if wp == nil {
+		panic("pbufio: nil default writer pool")
+	}
+	current.mu.Lock()
+	defer current.mu.Unlock()
+	prev = DefaultWriter()
+	current.writer.Store(wp)
+	return prev
+}
+
+// SetDefaultReader makes rp the ReaderPool used by package level functions
+// and returns the previous one. It is safe to call SetDefaultReader
+// concurrently with package level functions. It panics if rp is nil.
+func SetDefaultReader(rp *ReaderPool) (prev *ReaderPool) {
+	if rp == nil {
+		panic("pbufio: nil default reader pool")
+	}
+	current.mu.Lock()
+	defer current.mu.Unlock()
+	prev = DefaultReader()
+	current.reader.Store(rp)
+	return prev
+}
+
 // GetWriter returns bufio.Writer whose buffer has at least size bytes.
 // Note that size could be ceiled to the next power of two.
-// GetWriter is a wrapper around DefaultWriterPool.Get().
-func GetWriter(w io.Writer, size int) *bufio.Writer { return DefaultWriterPool.Get(w, size) }
+// GetWriter is a wrapper around DefaultWriter().Get().
+func GetWriter(w io.Writer, size int) *bufio.Writer { return DefaultWriter().Get(w, size) }
 
 // PutWriter takes bufio.Writer for future reuse.
 // It does not reuse bufio.Writer which underlying buffer size is not power of
-// PutWriter is a wrapper around DefaultWriterPool.Put().
-func PutWriter(bw *bufio.Writer) { DefaultWriterPool.Put(bw) }
+// PutWriter is a wrapper around DefaultWriter().Put().
+func PutWriter(bw *bufio.Writer) { DefaultWriter().Put(bw) }
+
+// FlushAndPutWriter flushes bufio.Writer and takes it for future reuse.
+// FlushAndPutWriter is a wrapper around DefaultWriter().FlushAndPut().
+func FlushAndPutWriter(bw *bufio.Writer) error { return DefaultWriter().FlushAndPut(bw) }
 
 // GetReader returns bufio.Reader whose buffer has at least size bytes. It returns
 // its capacity for further pass to Put().
 // Note that size could be ceiled to the next power of two.
-// GetReader is a wrapper around DefaultReaderPool.Get().
-func GetReader(w io.Reader, size int) *bufio.Reader { return DefaultReaderPool.Get(w, size) }
+// GetReader is a wrapper around DefaultReader().Get().
+func GetReader(w io.Reader, size int) *bufio.Reader { return DefaultReader().Get(w, size) }
 
 // PutReader takes bufio.Reader and its size for future reuse.
 // It does not reuse bufio.Reader if size is not power of two or is out of pool
 // min/max range.
-// PutReader is a wrapper around DefaultReaderPool.Put().
-func PutReader(bw *bufio.Reader) { DefaultReaderPool.Put(bw) }
+// PutReader is a wrapper around DefaultReader().Put().
+func PutReader(bw *bufio.Reader) { DefaultReader().Put(bw) }
+
+// ReleaseReader takes bufio.Reader for future reuse and returns a copy of
+// bytes which were buffered but not yet read from it.
+// ReleaseReader is a wrapper around DefaultReader().Release().
+func ReleaseReader(br *bufio.Reader) []byte { return DefaultReader().Release(br) }
+
+// GrowReader replaces bufio.Reader with the one whose buffer has at least size
+// bytes keeping buffered data.
+// GrowReader is a wrapper around DefaultReader().Grow().
+func GrowReader(br *bufio.Reader, r io.Reader, size int) *bufio.Reader {
+	return DefaultReader().Grow(br, r, size)
+}
 
 // WriterPool contains logic of *bufio.Writer reuse with various size.
 type WriterPool struct {
-	pool *pool.Pool
+	pool      *pool.Pool
+	strict    uint32
+	unflushed uint64
 }
 
 // NewWriterPool creates new WriterPool that reuses writers which size is in
 // logarithmic range [min, max].
 func NewWriterPool(min, max int) *WriterPool {
-	return &WriterPool{pool.New(min, max)}
+	return CustomWriterPool(
+		pool.WithLogSizeMapping(),
+		pool.WithLogSizeRange(min, max),
+	)
 }
 
 // CustomWriterPool creates new WriterPool with given options.
+// Note that pool.WithReset() and pool.WithResetter() options are overridden,
+// since the pool resets bufio objects on its own.
 func CustomWriterPool(opts ...pool.Option) *WriterPool {
-	return &WriterPool{pool.Custom(opts...)}
+	opts = append(opts[:len(opts):len(opts)], pool.WithReset(resetWriter))
+	return &WriterPool{pool: pool.Custom(opts...)}
 }
 
 // Get returns bufio.Writer whose buffer has at least size bytes.
@@ -59,17 +153,84 @@ func (wp *WriterPool) Get(w io.Writer, size int) *bufio.Writer {
 		bw.Reset(w)
 		return bw
 	}
-	return bufio.NewWriterSize(w, n)
+	bw := bufio.NewWriterSize(w, n)
+	if t := wp.pool.Tracker(); t != nil {
+		t.OnAcquire(bw)
+	}
+	return bw
 }
 
 // Put takes ownership of bufio.Writer for further reuse.
+// Note that any unflushed data is discarded. Such puts are counted and could
+// be inspected by Unflushed() call; in strict mode they lead to panic. See
+// SetStrict().
 func (wp *WriterPool) Put(bw *bufio.Writer) {
-	// Should reset even if we do Reset() inside Get().
-	// This is done to prevent locking underlying io.Writer from GC.
-	bw.Reset(nil)
+	wp.checkUnflushed(bw)
 	wp.pool.Put(bw, writerSize(bw))
 }
 
+// SetStrict enables or disables strict mode of the pool. In strict mode Put()
+// panics if bufio.Writer has unflushed data.
+// It is safe to call SetStrict concurrently with other methods.
+func (wp *WriterPool) SetStrict(strict bool) {
+	var v uint32
+	if strict {
+		v = 1
+	}
+	atomic.StoreUint32(&wp.strict, v)
+}
+
+// Unflushed returns number of writers put into the pool with unflushed data.
+func (wp *WriterPool) Unflushed() uint64 {
+	return atomic.LoadUint64(&wp.unflushed)
+}
+
+func (wp *WriterPool) checkUnflushed(bw *bufio.Writer) {
+	if bw == nil || bw.Buffered() == 0 {
+		return
+	}
+	atomic.AddUint64(&wp.unflushed, 1)
+	if atomic.LoadUint32(&wp.strict) != 0 {
+		panic("pbufio: put of bufio.Writer with unflushed data")
+	}
+}
+
+// Name returns the name given to the pool by pool.WithName() option.
+func (wp *WriterPool) Name() string {
+	return wp.pool.Name()
+}
+
+// Reconfigure atomically replaces buffer sizes reused by the pool with the
+// ones described by given options. See pool.Pool.Reconfigure().
+func (wp *WriterPool) Reconfigure(opts ...pool.Option) {
+	wp.pool.Reconfigure(opts...)
+}
+
+// Sizes returns sorted list of buffer sizes reused by the pool.
+func (wp *WriterPool) Sizes() []int {
+	return wp.pool.Sizes()
+}
+
+// Observer returns pool.Observer installed by pool.WithObserver() option.
+func (wp *WriterPool) Observer() pool.Observer {
+	return wp.pool.Observer()
+}
+
+// FlushAndPut flushes any buffered data to the underlying io.Writer and then
+// takes ownership of bufio.Writer for further reuse. Note that bw is reused
+// even if flush fails; the flush error is returned to the caller.
+func (wp *WriterPool) FlushAndPut(bw *bufio.Writer) error {
+	err := bw.Flush()
+	if err != nil {
+		// Error is reported to the caller, so do not treat data left in
+		// the buffer as unnoticed loss.
+		wp.pool.Put(bw, writerSize(bw))
+		return err
+	}
+	wp.Put(bw)
+	return nil
+}
+
 // ReaderPool contains logic of *bufio.Reader reuse with various size.
 type ReaderPool struct {
 	pool *pool.Pool
@@ -78,11 +239,17 @@ type ReaderPool struct {
 // NewReaderPool creates new ReaderPool that reuses writers which size is in
 // logarithmic range [min, max].
 func NewReaderPool(min, max int) *ReaderPool {
-	return &ReaderPool{pool.New(min, max)}
+	return CustomReaderPool(
+		pool.WithLogSizeMapping(),
+		pool.WithLogSizeRange(min, max),
+	)
 }
 
 // CustomReaderPool creates new ReaderPool with given options.
+// Note that pool.WithReset() and pool.WithResetter() options are overridden,
+// since the pool resets bufio objects on its own.
 func CustomReaderPool(opts ...pool.Option) *ReaderPool {
+	opts = append(opts[:len(opts):len(opts)], pool.WithReset(resetReader))
 	return &ReaderPool{pool.Custom(opts...)}
 }
 
@@ -94,13 +261,93 @@ func (rp *ReaderPool) Get(r io.Reader, size int) *bufio.Reader {
 		br.Reset(r)
 		return br
 	}
-	return bufio.NewReaderSize(r, n)
+	br := bufio.NewReaderSize(r, n)
+	if t := rp.pool.Tracker(); t != nil {
+		t.OnAcquire(br)
+	}
+	return br
 }
 
 // Put takes ownership of bufio.Reader for further reuse.
 func (rp *ReaderPool) Put(br *bufio.Reader) {
-	// Should reset even if we do Reset() inside Get().
-	// This is done to prevent locking underlying io.Reader from GC.
-	br.Reset(nil)
 	rp.pool.Put(br, readerSize(br))
 }
+
+// Name returns the name given to the pool by pool.WithName() option.
+func (rp *ReaderPool) Name() string {
+	return rp.pool.Name()
+}
+
+// Reconfigure atomically replaces buffer sizes reused by the pool with the
+// ones described by given options. See pool.Pool.Reconfigure().
+func (rp *ReaderPool) Reconfigure(opts ...pool.Option) {
+	rp.pool.Reconfigure(opts...)
+}
+
+// Sizes returns sorted list of buffer sizes reused by the pool.
+func (rp *ReaderPool) Sizes() []int {
+	return rp.pool.Sizes()
+}
+
+// Observer returns pool.Observer installed by pool.WithObserver() option.
+func (rp *ReaderPool) Observer() pool.Observer {
+	return rp.pool.Observer()
+}
+
+// Release takes ownership of bufio.Reader for further reuse just like Put()
+// does, but returns a copy of bytes which were buffered but not yet read from
+// br. That is, it could be used to salvage already received data, for example,
+// when connection is hijacked. It returns nil if there were no buffered bytes.
+func (rp *ReaderPool) Release(br *bufio.Reader) (leftover []byte) {
+	if n := br.Buffered(); n > 0 {
+		// Peek() does not fail here because n bytes are already buffered.
+		p, _ := br.Peek(n)
+		leftover = make([]byte, n)
+		copy(leftover, p)
+	}
+	rp.Put(br)
+	return leftover
+}
+
+// Grow returns bufio.Reader whose buffer has at least size bytes and which
+// continues reading from r. All bytes buffered by br are transferred to the
+// returned reader without data loss, and br is returned to the pool.
+//
+// Since bufio.Reader does not expose its underlying io.Reader, r must be the
+// same io.Reader br was reading from.
+//
+// Note that size could be ceiled to the next power of two.
+func (rp *ReaderPool) Grow(br *bufio.Reader, r io.Reader, size int) *bufio.Reader {
+	n := br.Buffered()
+	if n == 0 {
+		rp.Put(br)
+		return rp.Get(r, size)
+	}
+	if size < n {
+		size = n
+	}
+	// Peek() does not fail here because n bytes are already buffered.
+	p, _ := br.Peek(n)
+	bigger := rp.Get(io.MultiReader(bytes.NewReader(p), r), size)
+	// Fill bigger buffer with the buffered bytes. Note that bytes.Reader
+	// returns all n bytes in a single Read() call, so no bytes are read from
+	// r here. After that bytes.Reader is never read again except of io.EOF
+	// check, so it is safe to put br back to the pool.
+	bigger.Peek(n)
+	rp.Put(br)
+	return bigger
+}
+
+// resetWriter resets bufio.Writer when it is taken for further reuse.
+// Should reset even if we do Reset() inside Get().
+// This is done to prevent locking underlying io.Writer from GC.
+func resetWriter(x interface{}) {
+	x.(*bufio.Writer).Reset(nil)
+}
+
+// resetReader resets bufio.Reader when it is taken for further reuse.
+// Should reset even if we do Reset() inside Get().
+// This is done to prevent locking underlying io.Reader from GC.
+func resetReader(x interface{}) {
+	x.(*bufio.Reader).Reset(nil)
+}
@@ -1,6 +1,12 @@
 package pbufio
 
-import "testing"
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
 
 func TestGetWriter(t *testing.T) {
 	for _, test := range []struct {
@@ -61,3 +67,180 @@ func TestGetReader(t *testing.T) {
 		})
 	}
 }
+
+func TestReaderPoolRelease(t *testing.T) {
+	p := NewReaderPool(0, 64)
+
+	br := p.Get(strings.NewReader("hello, world"), 64)
+	if _, err := br.Peek(1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := br.Discard(7); err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := string(p.Release(br)), "world"; act != exp {
+		t.Errorf("Release() = %q; want %q", act, exp)
+	}
+
+	br = p.Get(strings.NewReader(""), 64)
+	if act := p.Release(br); act != nil {
+		t.Errorf("Release() = %q; want nil", act)
+	}
+}
+
+func TestWriterPoolFlushAndPut(t *testing.T) {
+	p := NewWriterPool(0, 64)
+
+	var buf bytes.Buffer
+	bw := p.Get(&buf, 64)
+	bw.WriteString("hello, world")
+	if err := p.FlushAndPut(bw); err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := buf.String(), "hello, world"; act != exp {
+		t.Errorf("unexpected written data: %q; want %q", act, exp)
+	}
+
+	bw = p.Get(errWriter{}, 64)
+	bw.WriteString("hello, world")
+	if err := p.FlushAndPut(bw); err != errWrite {
+		t.Errorf("FlushAndPut() = %v; want %v", err, errWrite)
+	}
+}
+
+func TestWriterPoolStrict(t *testing.T) {
+	p := NewWriterPool(0, 64)
+
+	bw := p.Get(ioutil.Discard, 64)
+	bw.WriteString("hello")
+	p.Put(bw)
+	if n := p.Unflushed(); n != 1 {
+		t.Errorf("Unflushed() = %d; want 1", n)
+	}
+
+	bw = p.Get(errWriter{}, 64)
+	bw.WriteString("hello")
+	p.FlushAndPut(bw)
+	if n := p.Unflushed(); n != 1 {
+		t.Errorf("Unflushed() = %d; want 1 after failed FlushAndPut()", n)
+	}
+
+	p.SetStrict(true)
+	p.Put(p.Get(ioutil.Discard, 64))
+
+	h := p.Acquire(ioutil.Discard, 64)
+	h.Writer().WriteString("hello")
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("want panic on Release() in strict mode")
+			}
+		}()
+		h.Release()
+	}()
+	if n := p.Unflushed(); n != 2 {
+		t.Errorf("Unflushed() = %d; want 2", n)
+	}
+}
+
+var errWrite = errors.New("write error")
+
+type errWriter struct{}
+
+func (errWriter) Write([]byte) (int, error) { return 0, errWrite }
+
+func TestReaderPoolGrow(t *testing.T) {
+	p := NewReaderPool(16, 128)
+
+	data := strings.Repeat("x", 20) + strings.Repeat("y", 100)
+	r := strings.NewReader(data)
+	br := p.Get(r, 16)
+	if _, err := br.Discard(4); err != nil {
+		t.Fatal(err)
+	}
+	buffered := br.Buffered()
+
+	br = p.Grow(br, r, 128)
+	if n := readerSize(br); n != 128 {
+		t.Errorf("unexpected buffer size after Grow(): %d; want %d", n, 128)
+	}
+	if n := br.Buffered(); n != buffered {
+		t.Errorf("unexpected buffered bytes after Grow(): %d; want %d", n, buffered)
+	}
+	bts, err := ioutil.ReadAll(br)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := string(bts), data[4:]; act != exp {
+		t.Errorf("unexpected data after Grow(): %q; want %q", act, exp)
+	}
+}
+
+func TestReaderPoolAcquire(t *testing.T) {
+	p := NewReaderPool(0, 128)
+
+	h := p.Acquire(strings.NewReader("hello"), 60)
+	br := h.Reader()
+	if n := readerSize(br); n != 64 {
+		t.Errorf("unexpected Acquire() buffer size: %d; want %d", n, 64)
+	}
+	h.Release()
+	if h.Reader() != nil {
+		t.Fatalf("released handle returns reader")
+	}
+
+	// Note that sync.Pool may drop br (e.g. under the race detector).
+	h = p.Acquire(strings.NewReader("hello"), 64)
+	if n := readerSize(h.Reader()); n != 64 {
+		t.Errorf("unexpected Acquire() buffer size: %d; want %d", n, 64)
+	}
+	if bts, _ := ioutil.ReadAll(h.Reader()); string(bts) != "hello" {
+		t.Fatalf("unexpected data read from reused reader: %q", bts)
+	}
+	h.Release()
+}
+
+func TestWriterPoolAcquire(t *testing.T) {
+	p := NewWriterPool(0, 128)
+
+	var buf bytes.Buffer
+	h := p.Acquire(&buf, 60)
+	bw := h.Writer()
+	if n := bw.Available(); n != 64 {
+		t.Errorf("unexpected Acquire() buffer size: %d; want %d", n, 64)
+	}
+	h.Release()
+
+	// Note that sync.Pool may drop bw (e.g. under the race detector).
+	h = p.Acquire(&buf, 64)
+	if n := h.Writer().Available(); n != 64 {
+		t.Errorf("unexpected Acquire() buffer size: %d; want %d", n, 64)
+	}
+	h.Writer().WriteString("hello")
+	h.Writer().Flush()
+	if buf.String() != "hello" {
+		t.Fatalf("unexpected data written by reused writer: %q", buf.String())
+	}
+	h.Release()
+}
+
+func TestSetDefault(t *testing.T) {
+	wp := NewWriterPool(0, 32)
+	if prev := SetDefaultWriter(wp); prev != DefaultWriterPool {
+		t.Errorf("SetDefaultWriter() = %p; want DefaultWriterPool", prev)
+	}
+	defer SetDefaultWriter(DefaultWriterPool)
+
+	rp := NewReaderPool(0, 32)
+	if prev := SetDefaultReader(rp); prev != DefaultReaderPool {
+		t.Errorf("SetDefaultReader() = %p; want DefaultReaderPool", prev)
+	}
+	defer SetDefaultReader(DefaultReaderPool)
+
+	if bw := GetWriter(ioutil.Discard, 10); bw.Size() != 16 {
+		t.Errorf("GetWriter() uses pool other than the default one")
+	}
+	if br := GetReader(strings.NewReader(""), 10); br.Size() != 16 {
+		t.Errorf("GetReader() uses pool other than the default one")
+	}
+}
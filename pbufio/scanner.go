@@ -0,0 +1,87 @@
+package pbufio
+
+import (
+	"bufio"
+	"io"
+	"sync"
+
+	"github.com/gobwas/pool"
+	"github.com/gobwas/pool/pbytes"
+)
+
+var DefaultScannerPool = NewScannerPool(256, 65536)
+
+// GetScanner returns Scanner whose token buffer has at least size bytes.
+// GetScanner is a wrapper around DefaultScannerPool.Get().
+func GetScanner(r io.Reader, size, max int, split bufio.SplitFunc) *Scanner {
+	return DefaultScannerPool.Get(r, size, max, split)
+}
+
+// PutScanner takes Scanner for future reuse.
+// PutScanner is a wrapper around DefaultScannerPool.Put().
+func PutScanner(s *Scanner) { DefaultScannerPool.Put(s) }
+
+// Scanner is a bufio.Scanner whose token buffer is taken from the pool.
+type Scanner struct {
+	bufio.Scanner
+	buf []byte
+}
+
+// ScannerPool contains logic of *Scanner reuse with various token buffer
+// size.
+type ScannerPool struct {
+	pool  sync.Pool
+	bytes *pbytes.Pool
+}
+
+// NewScannerPool creates new ScannerPool that reuses token buffers which size
+// is in logarithmic range [min, max].
+func NewScannerPool(min, max int) *ScannerPool {
+	return &ScannerPool{bytes: pbytes.New(min, max)}
+}
+
+// CustomScannerPool creates new ScannerPool with given options.
+func CustomScannerPool(opts ...pool.Option) *ScannerPool {
+	return &ScannerPool{bytes: pbytes.Custom(opts...)}
+}
+
+// Get returns Scanner reading from r whose initial token buffer has at least
+// size bytes. Tokens are limited by max bytes and are split by split
+// function. If max is not positive, bufio.MaxScanTokenSize is used; if split
+// is nil, bufio.ScanLines is used.
+//
+// Note that size could be ceiled to the next power of two.
+func (sp *ScannerPool) Get(r io.Reader, size, max int, split bufio.SplitFunc) *Scanner {
+	if max <= 0 {
+		max = bufio.MaxScanTokenSize
+	}
+	if split == nil {
+		split = bufio.ScanLines
+	}
+	s, _ := sp.pool.Get().(*Scanner)
+	if s == nil {
+		s = new(Scanner)
+	}
+	s.buf = sp.bytes.GetCap(size)
+	s.Scanner = *bufio.NewScanner(r)
+	buf := s.buf
+	if max < cap(buf) {
+		// bufio.Scanner limits tokens by max or by the capacity of given
+		// buffer, whichever is larger.
+		buf = buf[:0:max]
+	}
+	s.Scanner.Buffer(buf, max)
+	s.Scanner.Split(split)
+	return s
+}
+
+// Put takes ownership of Scanner and its token buffer for further reuse.
+// Tokens returned by s must not be used after Put().
+func (sp *ScannerPool) Put(s *Scanner) {
+	// Reset scanner to prevent locking underlying io.Reader and grown token
+	// buffer from GC.
+	s.Scanner = bufio.Scanner{}
+	sp.bytes.Put(s.buf)
+	s.buf = nil
+	sp.pool.Put(s)
+}
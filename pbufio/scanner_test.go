@@ -0,0 +1,67 @@
+package pbufio
+
+import (
+	"bufio"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestScannerPool(t *testing.T) {
+	p := NewScannerPool(0, 64)
+	for _, test := range []struct {
+		name  string
+		input string
+		size  int
+		max   int
+		split bufio.SplitFunc
+		exp   []string
+		err   error
+	}{
+		{
+			name:  "lines",
+			input: "foo\nbar\nbaz",
+			size:  8,
+			exp:   []string{"foo", "bar", "baz"},
+		},
+		{
+			name:  "words",
+			input: "foo bar baz",
+			size:  8,
+			split: bufio.ScanWords,
+			exp:   []string{"foo", "bar", "baz"},
+		},
+		{
+			name:  "grow",
+			input: strings.Repeat("x", 100) + "\ny",
+			size:  8,
+			exp:   []string{strings.Repeat("x", 100), "y"},
+		},
+		{
+			name:  "too long",
+			input: strings.Repeat("x", 20) + "\ny",
+			size:  32,
+			max:   16,
+			err:   bufio.ErrTooLong,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			s := p.Get(strings.NewReader(test.input), test.size, test.max, test.split)
+			defer p.Put(s)
+
+			if n := cap(s.buf); n < test.size {
+				t.Errorf("unexpected token buffer size: %d; want at least %d", n, test.size)
+			}
+			var act []string
+			for s.Scan() {
+				act = append(act, s.Text())
+			}
+			if err := s.Err(); err != test.err {
+				t.Fatalf("unexpected error: %v; want %v", err, test.err)
+			}
+			if !reflect.DeepEqual(act, test.exp) {
+				t.Errorf("unexpected tokens: %q; want %q", act, test.exp)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+//go:build !pool_sanitize
+// +build !pool_sanitize
+
+package pbytes
+
+import "github.com/gobwas/pool"
+
+// Acquire returns Handle of probably reused slice of bytes with at least
+// capacity of c and exactly len of n.
+// Acquire is a wrapper around Default().Acquire().
+func Acquire(n, c int) Handle { return Default().Acquire(n, c) }
+
+// Acquire returns Handle of probably reused slice of bytes with at least
+// capacity of c and exactly len of n. It could be returned to the pool by
+// Handle's Release() call.
+func (p *Pool) Acquire(n, c int) Handle {
+	if n > c {
+		panic("requested length is greater than capacity")
+	}
+
+	h := p.pool.Acquire(c)
+	if v := h.Value(); v != nil {
+		h.Set(v.([]byte)[:n])
+	} else {
+		h.Set(make([]byte, n, h.Size()))
+	}
+
+	return Handle{h}
+}
+
+// Handle binds slice of bytes to the pool it was taken from.
+type Handle struct {
+	h pool.Handle
+}
+
+// Bytes returns slice of bytes bound to the handle. It returns nil after
+// Release().
+func (h Handle) Bytes() []byte {
+	bts, _ := h.h.Value().([]byte)
+	return bts
+}
+
+// Release returns slice of bytes to the pool it was taken from. It panics if
+// handle is released more than once.
+func (h Handle) Release() {
+	h.h.Release()
+}
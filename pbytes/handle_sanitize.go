@@ -0,0 +1,36 @@
+//go:build pool_sanitize
+// +build pool_sanitize
+
+package pbytes
+
+import "sync/atomic"
+
+func Acquire(n, c int) Handle { return Default().Acquire(n, c) }
+
+func (p *Pool) Acquire(n, c int) Handle {
+	return Handle{
+		pool:     p,
+		bts:      p.Get(n, c),
+		released: new(uint32),
+	}
+}
+
+type Handle struct {
+	pool     *Pool
+	bts      []byte
+	released *uint32
+}
+
+func (h Handle) Bytes() []byte {
+	if atomic.LoadUint32(h.released) != 0 {
+		return nil
+	}
+	return h.bts
+}
+
+func (h Handle) Release() {
+	if !atomic.CompareAndSwapUint32(h.released, 0, 1) {
+		panic("pool: handle released more than once")
+	}
+	h.pool.Put(h.bts)
+}
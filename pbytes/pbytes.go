@@ -2,23 +2,62 @@
 // Note that by default it reuse slices with capacity from 128 to 65536 bytes.
 package pbytes
 
-// DefaultPool is used by pacakge level functions.
-var DefaultPool = New(128, 65536)
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultPool is the initial default pool used by package level functions.
+// It is registered by "pbytes.DefaultPool" name and could be configured by
+// pool.ConfigEnv environment variable. Its events could be counted by
+// pool.Counter enabled by pool.Settings.Stats.
+//
+// Note that assigning to DefaultPool does not change the pool used by package
+// level functions; use SetDefault() instead.
+var DefaultPool = defaultPool()
+
+var current struct {
+	mu sync.Mutex
+	v  atomic.Value
+}
+
+func init() {
+	current.v.Store(DefaultPool)
+}
+
+// Default returns the pool currently used by package level functions.
+func Default() *Pool {
+	return current.v.Load().(*Pool)
+}
+
+// SetDefault makes p the pool used by package level functions and returns
+// the previous one. It is safe to call SetDefault concurrently with package
+// level functions. It panics if p is nil.
+func SetDefault(p *Pool) (prev *Pool) {
+	if p == nil {
+		panic("pbytes: nil default pool")
+	}
+	current.mu.Lock()
+	defer current.mu.Unlock()
+	prev = Default()
+	current.v.Store(p)
+	return prev
+}
 
 // Get returns probably reused slice of bytes with at least capacity of c and
 // exactly len of n.
-// Get is a wrapper around DefaultPool.Get().
-func Get(n, c int) []byte { return DefaultPool.Get(n, c) }
+// Get is a wrapper around Default().Get().
+func Get(n, c int) []byte { return Default().Get(n, c) }
 
 // GetCap returns probably reused slice of bytes with at least capacity of n.
-// GetCap is a wrapper around DefaultPool.GetCap().
-func GetCap(c int) []byte { return DefaultPool.GetCap(c) }
+// GetCap is a wrapper around Default().GetCap().
+func GetCap(c int) []byte { return Default().GetCap(c) }
 
 // GetLen returns probably reused slice of bytes with at least capacity of n
 // and exactly len of n.
-// GetLen is a wrapper around DefaultPool.GetLen().
-func GetLen(n int) []byte { return DefaultPool.GetLen(n) }
+// GetLen is a wrapper around Default().GetLen().
+func GetLen(n int) []byte { return Default().GetLen(n) }
 
 // Put returns given slice to reuse pool.
-// Put is a wrapper around DefaultPool.Put().
-func Put(p []byte) { DefaultPool.Put(p) }
+// Put is a wrapper around Default().Put().
+func Put(p []byte) { Default().Put(p) }
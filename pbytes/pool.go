@@ -18,6 +18,13 @@ func New(min, max int) *Pool {
 	return &Pool{pool.New(min, max)}
 }
 
+func defaultPool() *Pool {
+	return Custom(pool.ConfigOptions("pbytes.DefaultPool",
+		pool.WithLogSizeMapping(),
+		pool.WithLogSizeRange(128, 65536),
+	)...)
+}
+
 // New creates new Pool with given options.
 func Custom(opts ...pool.Option) *Pool {
 	return &Pool{pool.Custom(opts...)}
@@ -37,7 +44,11 @@ func (p *Pool) Get(n, c int) []byte {
 		return bts
 	}
 
-	return make([]byte, n, x)
+	bts := make([]byte, n, x)
+	if t := p.pool.Tracker(); t != nil {
+		t.OnAcquire(bts)
+	}
+	return bts
 }
 
 // Put returns given slice to reuse pool.
@@ -57,3 +68,24 @@ func (p *Pool) GetCap(c int) []byte {
 func (p *Pool) GetLen(n int) []byte {
 	return p.Get(n, n)
 }
+
+// Name returns the name given to the pool by pool.WithName() option.
+func (p *Pool) Name() string {
+	return p.pool.Name()
+}
+
+// Reconfigure atomically replaces slice capacities reused by the pool with
+// the ones described by given options. See pool.Pool.Reconfigure().
+func (p *Pool) Reconfigure(opts ...pool.Option) {
+	p.pool.Reconfigure(opts...)
+}
+
+// Sizes returns sorted list of slice capacities reused by the pool.
+func (p *Pool) Sizes() []int {
+	return p.pool.Sizes()
+}
+
+// Observer returns pool.Observer installed by pool.WithObserver() option.
+func (p *Pool) Observer() pool.Observer {
+	return p.pool.Observer()
+}
@@ -1,3 +1,4 @@
+//go:build pool_sanitize
 // +build pool_sanitize
 
 package pbytes
@@ -9,6 +10,7 @@ import (
 	"syscall"
 	"unsafe"
 
+	"github.com/gobwas/pool"
 	"golang.org/x/sys/unix"
 )
 
@@ -22,14 +24,36 @@ type guard struct {
 
 const guardSize = int(unsafe.Sizeof(guard{}))
 
+// Pool contains logic of sanitizing byte slices usage. Slices are never
+// reused; instead, their memory is protected after Put() to detect access to
+// returned slices. The generic pool is kept only to reflect configuration.
 type Pool struct {
-	min, max int
+	pool *pool.Pool
+}
+
+func defaultPool() *Pool {
+	return Custom(pool.ConfigOptions("pbytes.DefaultPool",
+		pool.WithLogSizeMapping(),
+		pool.WithLogSizeRange(128, 65536),
+	)...)
 }
 
 func New(min, max int) *Pool {
-	return &Pool{min, max}
+	return Custom(
+		pool.WithLogSizeMapping(),
+		pool.WithLogSizeRange(min, max),
+	)
 }
 
+func Custom(opts ...pool.Option) *Pool {
+	return &Pool{pool.Custom(opts...)}
+}
+
+func (p *Pool) Name() string                    { return p.pool.Name() }
+func (p *Pool) Sizes() []int                    { return p.pool.Sizes() }
+func (p *Pool) Observer() pool.Observer         { return p.pool.Observer() }
+func (p *Pool) Reconfigure(opts ...pool.Option) { p.pool.Reconfigure(opts...) }
+
 // Get returns probably reused slice of bytes with at least capacity of c and
 // exactly len of n.
 func (p *Pool) Get(n, c int) []byte {
@@ -54,7 +78,7 @@ func (p *Pool) Get(n, c int) []byte {
 }
 
 func (p *Pool) GetCap(c int) []byte { return p.Get(0, c) }
-func (p *Pool) GetLen(n int) []byte { return Get(n, n) }
+func (p *Pool) GetLen(n int) []byte { return p.Get(n, n) }
 
 // Put returns given slice to reuse pool.
 func (p *Pool) Put(bts []byte) {
@@ -8,6 +8,8 @@ import (
 	"strconv"
 	"testing"
 	"unsafe"
+
+	"github.com/gobwas/pool"
 )
 
 func TestPoolGet(t *testing.T) {
@@ -109,3 +111,77 @@ func BenchmarkPool(b *testing.B) {
 		})
 	}
 }
+
+func TestPoolAcquire(t *testing.T) {
+	p := New(0, 32)
+
+	h := p.Acquire(5, 10)
+	b := h.Bytes()
+	if len(b) != 5 || cap(b) != 16 {
+		t.Fatalf("Acquire(5, 10) returned %d-len %d-cap slice; want 5-len 16-cap", len(b), cap(b))
+	}
+	h.Release()
+	if h.Bytes() != nil {
+		t.Fatalf("released handle returns bytes")
+	}
+
+	// Note that sync.Pool may drop b (e.g. under the race detector).
+	h = p.Acquire(16, 16)
+	if b := h.Bytes(); len(b) != 16 || cap(b) != 16 {
+		t.Fatalf("Acquire(16, 16) returned %d-len %d-cap slice; want 16-len 16-cap", len(b), cap(b))
+	}
+	h.Release()
+}
+
+type counter struct {
+	gets, puts, allocs int
+}
+
+func (c *counter) OnGet(int, int, bool) { c.gets++ }
+func (c *counter) OnPut(int, bool)      { c.puts++ }
+func (c *counter) OnAlloc(int)          { c.allocs++ }
+
+func TestPoolObserver(t *testing.T) {
+	var c counter
+	p := Custom(
+		pool.WithLogSizeMapping(),
+		pool.WithLogSizeRange(0, 32),
+		pool.WithObserver(&c),
+	)
+	p.Put(p.GetLen(10))
+	if c.gets != 1 || c.puts != 1 || c.allocs != 1 {
+		t.Errorf("unexpected observed events: %+v", c)
+	}
+}
+
+func TestSetDefault(t *testing.T) {
+	var c counter
+	p := Custom(
+		pool.WithLogSizeMapping(),
+		pool.WithLogSizeRange(0, 32),
+		pool.WithObserver(&c),
+	)
+	if prev := SetDefault(p); prev != DefaultPool {
+		t.Errorf("SetDefault() = %p; want DefaultPool", prev)
+	}
+	defer SetDefault(DefaultPool)
+
+	Put(GetLen(10))
+	if c.gets != 1 || c.puts != 1 {
+		t.Errorf("package level functions use pool other than the default one")
+	}
+}
+
+func TestPoolReconfigure(t *testing.T) {
+	p := New(0, 32)
+	p.Reconfigure(pool.WithLogSizeRange(64, 128))
+	if act, exp := p.Sizes(), []int{64, 128}; !reflect.DeepEqual(act, exp) {
+		t.Errorf("Sizes() = %v; want %v", act, exp)
+	}
+	if bts := p.GetLen(10); cap(bts) != 10 {
+		t.Errorf("GetLen(10) cap is %d; want 10", cap(bts))
+	}
+	if bts := p.GetLen(100); cap(bts) != 128 {
+		t.Errorf("GetLen(100) cap is %d; want 128", cap(bts))
+	}
+}
@@ -0,0 +1,173 @@
+// Package pcompress contains tools for pooling compress/gzip, compress/flate
+// and compress/zlib readers and writers.
+//
+// Writers are reused with respect to the compression level they were created
+// with. Since there is no way to get the level back from the writer, it must
+// be passed to Put*Writer() functions just like the size is passed to
+// pool.Put().
+package pcompress
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"sync"
+
+	"github.com/gobwas/pool"
+)
+
+var (
+	gzipWriters  = newWriterPool()
+	flateWriters = newWriterPool()
+	zlibWriters  = newWriterPool()
+
+	gzipReaders  sync.Pool
+	flateReaders sync.Pool
+	zlibReaders  sync.Pool
+)
+
+// GetGzipWriter returns gzip.Writer writing to w with given compression
+// level. It returns non-nil error only if level is not valid.
+func GetGzipWriter(w io.Writer, level int) (*gzip.Writer, error) {
+	if v, _ := gzipWriters.Get(level); v != nil {
+		zw := v.(*gzip.Writer)
+		zw.Reset(w)
+		return zw, nil
+	}
+	return gzip.NewWriterLevel(w, level)
+}
+
+// PutGzipWriter takes gzip.Writer created with given compression level for
+// future reuse.
+func PutGzipWriter(zw *gzip.Writer, level int) {
+	// Should reset even if we do Reset() inside Get().
+	// This is done to prevent locking underlying io.Writer from GC.
+	zw.Reset(nil)
+	gzipWriters.Put(zw, level)
+}
+
+// GetGzipReader returns gzip.Reader reading from r. It returns non-nil error
+// if gzip header could not be read from r.
+//
+// Note that gzip.Reader allocates new bufio.Reader on each reset unless r
+// implements io.ByteReader. Consider wrapping r into bufio.Reader taken from
+// pbufio to avoid that allocation.
+func GetGzipReader(r io.Reader) (*gzip.Reader, error) {
+	if zr, _ := gzipReaders.Get().(*gzip.Reader); zr != nil {
+		if err := zr.Reset(r); err != nil {
+			PutGzipReader(zr)
+			return nil, err
+		}
+		return zr, nil
+	}
+	return gzip.NewReader(r)
+}
+
+// PutGzipReader takes gzip.Reader for future reuse.
+func PutGzipReader(zr *gzip.Reader) {
+	zr.Reset(nopReader{})
+	gzipReaders.Put(zr)
+}
+
+// GetFlateWriter returns flate.Writer writing to w with given compression
+// level. It returns non-nil error only if level is not valid.
+func GetFlateWriter(w io.Writer, level int) (*flate.Writer, error) {
+	if v, _ := flateWriters.Get(level); v != nil {
+		fw := v.(*flate.Writer)
+		fw.Reset(w)
+		return fw, nil
+	}
+	return flate.NewWriter(w, level)
+}
+
+// PutFlateWriter takes flate.Writer created with given compression level for
+// future reuse.
+func PutFlateWriter(fw *flate.Writer, level int) {
+	fw.Reset(nil)
+	flateWriters.Put(fw, level)
+}
+
+// GetFlateReader returns flate decompressor reading from r.
+func GetFlateReader(r io.Reader) io.ReadCloser {
+	if fr, _ := flateReaders.Get().(io.ReadCloser); fr != nil {
+		fr.(flate.Resetter).Reset(r, nil)
+		return fr
+	}
+	return flate.NewReader(r)
+}
+
+// PutFlateReader takes flate decompressor returned by GetFlateReader() for
+// future reuse.
+func PutFlateReader(fr io.ReadCloser) {
+	// Flate decompressor keeps its bufio.Reader for the future resets only if
+	// it is reset by io.Reader which does not implement io.ByteReader.
+	fr.(flate.Resetter).Reset(eofReader{}, nil)
+	flateReaders.Put(fr)
+}
+
+// GetZlibWriter returns zlib.Writer writing to w with given compression
+// level. It returns non-nil error only if level is not valid.
+func GetZlibWriter(w io.Writer, level int) (*zlib.Writer, error) {
+	if v, _ := zlibWriters.Get(level); v != nil {
+		zw := v.(*zlib.Writer)
+		zw.Reset(w)
+		return zw, nil
+	}
+	return zlib.NewWriterLevel(w, level)
+}
+
+// PutZlibWriter takes zlib.Writer created with given compression level for
+// future reuse.
+func PutZlibWriter(zw *zlib.Writer, level int) {
+	zw.Reset(nil)
+	zlibWriters.Put(zw, level)
+}
+
+// GetZlibReader returns zlib decompressor reading from r. It returns non-nil
+// error if zlib header could not be read from r.
+//
+// Note that zlib decompressor allocates new bufio.Reader on each reset unless
+// r implements io.ByteReader. Consider wrapping r into bufio.Reader taken
+// from pbufio to avoid that allocation.
+func GetZlibReader(r io.Reader) (io.ReadCloser, error) {
+	if zr, _ := zlibReaders.Get().(io.ReadCloser); zr != nil {
+		if err := zr.(zlib.Resetter).Reset(r, nil); err != nil {
+			PutZlibReader(zr)
+			return nil, err
+		}
+		return zr, nil
+	}
+	return zlib.NewReader(r)
+}
+
+// PutZlibReader takes zlib decompressor returned by GetZlibReader() for
+// future reuse.
+func PutZlibReader(zr io.ReadCloser) {
+	zr.(zlib.Resetter).Reset(nopReader{}, nil)
+	zlibReaders.Put(zr)
+}
+
+// newWriterPool returns pool.Pool which reuses objects distinguishable by
+// compression level instead of size.
+func newWriterPool() *pool.Pool {
+	opts := []pool.Option{
+		pool.WithIdentitySizeMapping(),
+	}
+	for level := flate.HuffmanOnly; level <= flate.BestCompression; level++ {
+		opts = append(opts, pool.WithSize(level))
+	}
+	return pool.Custom(opts...)
+}
+
+// nopReader is used to reset readers on Put() instead of nil io.Reader
+// because most of decompressors start reading right after reset.
+type nopReader struct{}
+
+func (nopReader) Read([]byte) (int, error) { return 0, io.EOF }
+func (nopReader) ReadByte() (byte, error)  { return 0, io.EOF }
+
+// eofReader is like nopReader but does not implement io.ByteReader.
+type eofReader struct{}
+
+func (eofReader) Read([]byte) (int, error) { return 0, io.EOF }
@@ -0,0 +1,131 @@
+package pcompress
+
+import (
+	"bytes"
+	"compress/flate"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var data = strings.Repeat("hello, world! ", 100)
+
+func TestGzip(t *testing.T) {
+	for _, level := range []int{
+		flate.HuffmanOnly,
+		flate.DefaultCompression,
+		flate.NoCompression,
+		flate.BestSpeed,
+		flate.BestCompression,
+	} {
+		t.Run(strconv.Itoa(level), func(t *testing.T) {
+			// Run twice to ensure that reused objects work properly.
+			for i := 0; i < 2; i++ {
+				var buf bytes.Buffer
+				zw, err := GetGzipWriter(&buf, level)
+				if err != nil {
+					t.Fatal(err)
+				}
+				mustWrite(t, zw)
+				PutGzipWriter(zw, level)
+
+				zr, err := GetGzipReader(&buf)
+				if err != nil {
+					t.Fatal(err)
+				}
+				mustRead(t, zr)
+				PutGzipReader(zr)
+			}
+		})
+	}
+}
+
+func TestFlate(t *testing.T) {
+	for _, level := range []int{
+		flate.DefaultCompression,
+		flate.BestCompression,
+	} {
+		t.Run(strconv.Itoa(level), func(t *testing.T) {
+			for i := 0; i < 2; i++ {
+				var buf bytes.Buffer
+				fw, err := GetFlateWriter(&buf, level)
+				if err != nil {
+					t.Fatal(err)
+				}
+				mustWrite(t, fw)
+				PutFlateWriter(fw, level)
+
+				fr := GetFlateReader(&buf)
+				mustRead(t, fr)
+				PutFlateReader(fr)
+			}
+		})
+	}
+}
+
+func TestZlib(t *testing.T) {
+	for _, level := range []int{
+		flate.DefaultCompression,
+		flate.BestCompression,
+	} {
+		t.Run(strconv.Itoa(level), func(t *testing.T) {
+			for i := 0; i < 2; i++ {
+				var buf bytes.Buffer
+				zw, err := GetZlibWriter(&buf, level)
+				if err != nil {
+					t.Fatal(err)
+				}
+				mustWrite(t, zw)
+				PutZlibWriter(zw, level)
+
+				zr, err := GetZlibReader(&buf)
+				if err != nil {
+					t.Fatal(err)
+				}
+				mustRead(t, zr)
+				PutZlibReader(zr)
+			}
+		})
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	if _, err := GetGzipWriter(ioutil.Discard, 42); err == nil {
+		t.Errorf("GetGzipWriter() with invalid level: want error")
+	}
+	if _, err := GetFlateWriter(ioutil.Discard, 42); err == nil {
+		t.Errorf("GetFlateWriter() with invalid level: want error")
+	}
+	if _, err := GetZlibWriter(ioutil.Discard, 42); err == nil {
+		t.Errorf("GetZlibWriter() with invalid level: want error")
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := GetGzipReader(strings.NewReader("not a gzip")); err == nil {
+			t.Errorf("GetGzipReader() with invalid data: want error")
+		}
+		if _, err := GetZlibReader(strings.NewReader("not a zlib")); err == nil {
+			t.Errorf("GetZlibReader() with invalid data: want error")
+		}
+	}
+}
+
+func mustWrite(t *testing.T, w io.WriteCloser) {
+	if _, err := io.WriteString(w, data); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func mustRead(t *testing.T, r io.Reader) {
+	bts, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act := string(bts); act != data {
+		t.Fatalf("unexpected decompressed data: %q; want %q", act, data)
+	}
+}
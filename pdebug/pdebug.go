@@ -0,0 +1,114 @@
+// Package pdebug serves via its HTTP server runtime information about the
+// named pools.
+//
+// The package is typically only imported for the side effect of registering
+// its HTTP handler. The handled path is /debug/pools.
+//
+// To use pdebug, link this package into your program:
+//
+//   import _ "github.com/gobwas/pool/pdebug"
+//
+// Only pools named by pool.WithName() option are served. Note that the
+// default pools of this module are named. Counters of hits and misses are
+// reported only for pools whose Observer has Stats() method returning
+// pool.Stats, such as *pool.Counter. It could be installed to the default
+// pools by pool.Settings.Stats, or explicitly to other pools:
+//
+//   var bytesPool = pbytes.Custom(
+//       pool.WithName("bytes"),
+//       pool.WithLogSizeMapping(),
+//       pool.WithLogSizeRange(128, 65536),
+//       pool.WithObserver(new(pool.Counter)),
+//   )
+//
+package pdebug
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/gobwas/pool"
+)
+
+func init() {
+	http.Handle("/debug/pools", Handler())
+}
+
+// Info describes registered pool.
+type Info struct {
+	Name  string      `json:"name"`
+	Sizes []int       `json:"sizes"`
+	Stats *pool.Stats `json:"stats,omitempty"`
+}
+
+// Pools returns information about all registered pools sorted by name.
+func Pools() []Info {
+	var ret []Info
+	pool.Range(func(name string, p *pool.Pool) bool {
+		info := Info{
+			Name:  name,
+			Sizes: p.Sizes(),
+		}
+		if c, ok := p.Observer().(interface{ Stats() pool.Stats }); ok {
+			s := c.Stats()
+			info.Stats = &s
+		}
+		ret = append(ret, info)
+		return true
+	})
+	return ret
+}
+
+// Handler returns an HTTP handler that serves information about registered
+// pools. It responds with JSON if request has "format=json" query parameter
+// or accepts "application/json" content; otherwise it responds with HTML.
+func Handler() http.Handler {
+	return http.HandlerFunc(serve)
+}
+
+func serve(w http.ResponseWriter, r *http.Request) {
+	pools := Pools()
+	if r.FormValue("format") == "json" || strings.Contains(r.Header.Get("Accept"), "application/json") {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.Encode(pools)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := page.Execute(w, pools); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+var page = template.Must(template.New("pools").Parse(`<html>
+<head>
+<title>/debug/pools</title>
+<style>
+table { border-collapse: collapse; }
+td, th { border: 1px solid #ccc; padding: 2px 8px; text-align: right; }
+</style>
+</head>
+<body>
+<p><a href="?format=json">json</a></p>
+<table>
+<tr>
+<th>name</th><th>sizes</th>
+<th>gets</th><th>hits</th><th>misses</th><th>allocs</th><th>puts</th><th>drops</th><th>put bytes</th><th>reused bytes</th>
+</tr>
+{{range .}}
+<tr>
+<td>{{.Name}}</td><td>{{range $i, $n := .Sizes}}{{if $i}} {{end}}{{$n}}{{end}}</td>
+{{with .Stats}}
+<td>{{.Gets}}</td><td>{{.Hits}}</td><td>{{.Misses}}</td><td>{{.Allocs}}</td><td>{{.Puts}}</td><td>{{.Drops}}</td><td>{{.PutBytes}}</td><td>{{.ReusedBytes}}</td>
+{{else}}
+<td colspan="8">no counters</td>
+{{end}}
+</tr>
+{{end}}
+</table>
+</body>
+</html>
+`))
@@ -0,0 +1,53 @@
+package pdebug
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gobwas/pool"
+	_ "github.com/gobwas/pool/pbufio"
+	"github.com/gobwas/pool/pbytes"
+)
+
+var testPool = pbytes.Custom(
+	pool.WithName("pdebug.test"),
+	pool.WithLogSizeMapping(),
+	pool.WithLogSizeRange(16, 64),
+	pool.WithObserver(new(pool.Counter)),
+)
+
+func TestHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/debug/pools?format=json", nil))
+
+	var pools []Info
+	if err := json.NewDecoder(rec.Body).Decode(&pools); err != nil {
+		t.Fatal(err)
+	}
+	names := make(map[string]Info)
+	for _, info := range pools {
+		names[info.Name] = info
+	}
+	for _, name := range []string{
+		"pdebug.test",
+		"pool.DefaultPool",
+		"pbytes.DefaultPool",
+		"pbufio.DefaultReaderPool",
+		"pbufio.DefaultWriterPool",
+	} {
+		if _, has := names[name]; !has {
+			t.Errorf("no %q pool in response", name)
+		}
+	}
+	if info := names["pdebug.test"]; info.Stats == nil || len(info.Sizes) != 3 {
+		t.Errorf("unexpected info of test pool: %+v", info)
+	}
+
+	rec = httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/debug/pools", nil))
+	if body := rec.Body.String(); !strings.Contains(body, "<td>pdebug.test</td>") {
+		t.Errorf("no test pool in HTML response:\n%s", body)
+	}
+}
@@ -0,0 +1,92 @@
+// Package pencoding contains helpers for encoding/json and encoding/binary
+// whose buffers are taken from pbytes.Default().
+package pencoding
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"sync"
+
+	"github.com/gobwas/pool/internal/pbuffer"
+	"github.com/gobwas/pool/pbytes"
+)
+
+// MarshalJSON returns the JSON encoding of v just like json.Marshal() does.
+// Returned buf is taken from pbytes.Default() and must not be used after
+// release call. Release must be called exactly once.
+func MarshalJSON(v interface{}) (buf []byte, release func(), err error) {
+	e := jsonEncoders.Get().(*jsonEncoder)
+	err = e.enc.Encode(v)
+	buf = e.buf.Detach()
+	jsonEncoders.Put(e)
+	if err != nil {
+		// Encoder could fail before writing anything to the buffer.
+		if buf != nil {
+			pbytes.Put(buf)
+		}
+		return nil, nil, err
+	}
+	// Trim trailing newline added by json.Encoder to be consistent with
+	// json.Marshal().
+	buf = buf[:len(buf)-1]
+	return buf, func() { pbytes.Put(buf) }, nil
+}
+
+// WriteJSON writes the JSON encoding of v to w with single Write() call.
+func WriteJSON(w io.Writer, v interface{}) error {
+	buf, release, err := MarshalJSON(v)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(buf)
+	release()
+	return err
+}
+
+// ReadJSON reads r until EOF and stores the JSON-encoded data in the value
+// pointed to by v.
+func ReadJSON(r io.Reader, v interface{}) error {
+	var buf pbuffer.Buffer
+	_, err := buf.ReadFrom(r)
+	if err == nil {
+		err = json.Unmarshal(buf.Bytes(), v)
+	}
+	buf.Release()
+	return err
+}
+
+// MarshalBinary returns the binary representation of v in given byte order
+// just like binary.Write() does. Returned buf is taken from
+// pbytes.Default() and must not be used after release call. Release must be
+// called exactly once.
+func MarshalBinary(order binary.ByteOrder, v interface{}) (buf []byte, release func(), err error) {
+	n := binary.Size(v)
+	if n < 0 {
+		// Let the binary package describe the error.
+		return nil, nil, binary.Write(ioutil.Discard, order, v)
+	}
+	var b pbuffer.Buffer
+	b.Grow(n)
+	if err = binary.Write(&b, order, v); err != nil {
+		b.Release()
+		return nil, nil, err
+	}
+	buf = b.Detach()
+	return buf, func() { pbytes.Put(buf) }, nil
+}
+
+var jsonEncoders = sync.Pool{
+	New: func() interface{} {
+		e := new(jsonEncoder)
+		e.enc = json.NewEncoder(&e.buf)
+		return e
+	},
+}
+
+// jsonEncoder holds json.Encoder bound to the buffer.
+type jsonEncoder struct {
+	buf pbuffer.Buffer
+	enc *json.Encoder
+}
@@ -0,0 +1,81 @@
+package pencoding
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type record struct {
+	Name  string   `json:"name"`
+	Tags  []string `json:"tags"`
+	Value int      `json:"value"`
+}
+
+var rec = record{
+	Name:  "<hello>",
+	Tags:  []string{"a", "b", strings.Repeat("c", 1000)},
+	Value: 42,
+}
+
+func TestMarshalJSON(t *testing.T) {
+	for i := 0; i < 2; i++ {
+		exp, err := json.Marshal(rec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		act, release, err := MarshalJSON(rec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(act, exp) {
+			t.Errorf("MarshalJSON() = %s; want %s", act, exp)
+		}
+		release()
+	}
+	if _, _, err := MarshalJSON(make(chan int)); err == nil {
+		t.Errorf("MarshalJSON() of channel: want error")
+	}
+}
+
+func TestWriteReadJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, rec); err != nil {
+		t.Fatal(err)
+	}
+	var act record
+	if err := ReadJSON(&buf, &act); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(act, rec) {
+		t.Errorf("ReadJSON() decoded %+v; want %+v", act, rec)
+	}
+}
+
+func TestMarshalBinary(t *testing.T) {
+	v := struct {
+		A uint16
+		B int64
+		C [3]byte
+	}{0xabcd, -1, [3]byte{1, 2, 3}}
+
+	var exp bytes.Buffer
+	if err := binary.Write(&exp, binary.BigEndian, v); err != nil {
+		t.Fatal(err)
+	}
+	act, release, err := MarshalBinary(binary.BigEndian, v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(act, exp.Bytes()) {
+		t.Errorf("MarshalBinary() = %x; want %x", act, exp.Bytes())
+	}
+	release()
+
+	if _, _, err := MarshalBinary(binary.BigEndian, "string"); err == nil {
+		t.Errorf("MarshalBinary() of string: want error")
+	}
+}
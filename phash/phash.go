@@ -0,0 +1,93 @@
+// Package phash contains tools for pooling hash.Hash instances.
+package phash
+
+import (
+	"crypto"
+	"hash"
+	"hash/adler32"
+	"hash/crc32"
+	"hash/fnv"
+	"strconv"
+	"sync"
+
+	"github.com/gobwas/pool/pbytes"
+)
+
+// Pools of non-cryptographic hash functions.
+var (
+	CRC32   = New(func() hash.Hash { return crc32.NewIEEE() })
+	Adler32 = New(func() hash.Hash { return adler32.New() })
+	FNV32   = New(func() hash.Hash { return fnv.New32() })
+	FNV32a  = New(func() hash.Hash { return fnv.New32a() })
+	FNV64   = New(func() hash.Hash { return fnv.New64() })
+	FNV64a  = New(func() hash.Hash { return fnv.New64a() })
+)
+
+var cryptoPools [crypto.BLAKE2b_512 + 1]*Pool
+
+func init() {
+	for h := crypto.MD4; h <= crypto.BLAKE2b_512; h++ {
+		cryptoPools[h] = New(h.New)
+	}
+}
+
+// Crypto returns Pool of hash functions of type h.
+// Note that just like with crypto.Hash.New(), the package implementing the
+// hash function must be linked into the binary.
+func Crypto(h crypto.Hash) *Pool {
+	if h <= 0 || int(h) >= len(cryptoPools) {
+		panic("phash: unknown hash function #" + strconv.Itoa(int(h)))
+	}
+	return cryptoPools[h]
+}
+
+// Get returns hash function of type h.
+// Get is a wrapper around Crypto(h).Get().
+func Get(h crypto.Hash) hash.Hash { return Crypto(h).Get() }
+
+// Put takes hash function of type h for future reuse.
+// Put is a wrapper around Crypto(h).Put().
+func Put(h crypto.Hash, x hash.Hash) { Crypto(h).Put(x) }
+
+// Sum returns checksum of data computed by hash function of type h.
+// Sum is a wrapper around Crypto(h).Sum().
+func Sum(h crypto.Hash, data, dst []byte) []byte { return Crypto(h).Sum(data, dst) }
+
+// Pool contains logic of reusing hash functions of the same type.
+type Pool struct {
+	pool sync.Pool
+}
+
+// New creates new Pool that reuses hash functions created by fn.
+func New(fn func() hash.Hash) *Pool {
+	p := new(Pool)
+	p.pool.New = func() interface{} {
+		return fn()
+	}
+	return p
+}
+
+// Get returns probably reused hash function in its initial state.
+func (p *Pool) Get() hash.Hash {
+	return p.pool.Get().(hash.Hash)
+}
+
+// Put resets given hash function and takes it for future reuse.
+func (p *Pool) Put(h hash.Hash) {
+	h.Reset()
+	p.pool.Put(h)
+}
+
+// Sum appends checksum of data to dst and returns the resulting slice.
+// If dst is nil, it is taken from pbytes.Default(), thus it could be
+// returned there by pbytes.Put() when it is no longer needed.
+func (p *Pool) Sum(data, dst []byte) []byte {
+	h := p.Get()
+	h.Write(data)
+	if dst == nil {
+		dst = pbytes.GetCap(h.Size())
+	}
+	dst = h.Sum(dst)
+	p.Put(h)
+	return dst
+}
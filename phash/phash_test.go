@@ -0,0 +1,54 @@
+package phash
+
+import (
+	"bytes"
+	"crypto"
+	_ "crypto/sha1"
+	"crypto/sha256"
+	"hash/crc32"
+	"testing"
+)
+
+var data = []byte("hello, world")
+
+func TestSum(t *testing.T) {
+	for i := 0; i < 2; i++ {
+		exp := sha256.Sum256(data)
+		act := Sum(crypto.SHA256, data, nil)
+		if !bytes.Equal(act, exp[:]) {
+			t.Errorf("Sum() = %x; want %x", act, exp)
+		}
+	}
+
+	exp := crc32.NewIEEE()
+	exp.Write(data)
+	act := CRC32.Sum(data, []byte("crc:"))
+	if !bytes.Equal(act, exp.Sum([]byte("crc:"))) {
+		t.Errorf("Sum() = %x; want %x", act, exp.Sum([]byte("crc:")))
+	}
+}
+
+func TestGetPut(t *testing.T) {
+	h := Get(crypto.SHA1)
+	h.Write([]byte("garbage"))
+	Put(crypto.SHA1, h)
+
+	exp := crypto.SHA1.New()
+	exp.Write(data)
+
+	h = Get(crypto.SHA1)
+	h.Write(data)
+	if act := h.Sum(nil); !bytes.Equal(act, exp.Sum(nil)) {
+		t.Errorf("unexpected sum of reused hash: %x; want %x", act, exp.Sum(nil))
+	}
+	Put(crypto.SHA1, h)
+}
+
+func TestCryptoUnknown(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("want panic")
+		}
+	}()
+	Crypto(0)
+}
@@ -0,0 +1,169 @@
+// Package phttp contains net/http middleware whose memory is taken from the
+// pools.
+package phttp
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gobwas/pool/internal/pbuffer"
+	"github.com/gobwas/pool/pbytes"
+	"github.com/gobwas/pool/pcompress"
+)
+
+// Buffer returns http.Handler which buffers whole response of h in memory
+// taken from pbytes.Default(). That is, it sets Content-Length header of
+// the response and writes its body with single Write() call. All buffers are
+// returned to the pool after the response is written.
+//
+// Note that h must not hold http.ResponseWriter after it returns.
+func Buffer(h http.Handler) http.Handler {
+	return &handler{
+		handler: h,
+	}
+}
+
+// BufferGzip is like Buffer() but also compresses response body with gzip of
+// given compression level if the client accepts it and the response is not
+// encoded yet. Compressing writers are taken from pcompress package.
+//
+// It panics if level is not valid.
+func BufferGzip(h http.Handler, level int) http.Handler {
+	zw, err := pcompress.GetGzipWriter(nil, level)
+	if err != nil {
+		panic(err)
+	}
+	pcompress.PutGzipWriter(zw, level)
+
+	return &handler{
+		handler: h,
+		gzip:    true,
+		level:   level,
+	}
+}
+
+type handler struct {
+	handler http.Handler
+	gzip    bool
+	level   int
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rw := responseWriters.Get().(*responseWriter)
+	rw.w = w
+	h.handler.ServeHTTP(rw, r)
+
+	status := rw.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	body := rw.buf.Detach()
+	*rw = responseWriter{}
+	responseWriters.Put(rw)
+
+	header := w.Header()
+	if bodyAllowed(status) {
+		if h.gzip && len(body) > 0 && header.Get("Content-Encoding") == "" && acceptsGzip(r) {
+			// Net/http does not sniff the content type of encoded response,
+			// so it must be done before compression.
+			if header.Get("Content-Type") == "" {
+				header.Set("Content-Type", http.DetectContentType(body))
+			}
+			body = h.compress(body)
+			header.Set("Content-Encoding", "gzip")
+			header.Add("Vary", "Accept-Encoding")
+		}
+		// Response to HEAD request has no body, so Content-Length set by the
+		// handler is kept. The same is done for empty body.
+		if r.Method != http.MethodHead && (len(body) > 0 || header.Get("Content-Length") == "") {
+			header.Set("Content-Length", strconv.Itoa(len(body)))
+		}
+	}
+	w.WriteHeader(status)
+	if len(body) > 0 {
+		w.Write(body)
+	}
+	if body != nil {
+		pbytes.Put(body)
+	}
+}
+
+// compress returns gzip-compressed body. Given body is returned to the pool.
+func (h *handler) compress(body []byte) []byte {
+	var buf pbuffer.Buffer
+	// Level was checked inside BufferGzip(), so error is not possible here.
+	zw, _ := pcompress.GetGzipWriter(&buf, h.level)
+	zw.Write(body)
+	zw.Close()
+	pcompress.PutGzipWriter(zw, h.level)
+	pbytes.Put(body)
+	return buf.Detach()
+}
+
+var responseWriters = sync.Pool{
+	New: func() interface{} {
+		return new(responseWriter)
+	},
+}
+
+// responseWriter is an http.ResponseWriter which buffers response body.
+type responseWriter struct {
+	w      http.ResponseWriter
+	status int
+	buf    pbuffer.Buffer
+}
+
+func (rw *responseWriter) Header() http.Header {
+	return rw.w.Header()
+}
+
+func (rw *responseWriter) WriteHeader(status int) {
+	if rw.status == 0 {
+		rw.status = status
+	}
+}
+
+func (rw *responseWriter) Write(p []byte) (int, error) {
+	if rw.status == 0 {
+		rw.status = http.StatusOK
+	}
+	return rw.buf.Write(p)
+}
+
+// bodyAllowed reports whether a given response status code permits a body.
+// See RFC 7230, section 3.3.
+func bodyAllowed(status int) bool {
+	switch {
+	case status >= 100 && status <= 199:
+		return false
+	case status == http.StatusNoContent:
+		return false
+	case status == http.StatusNotModified:
+		return false
+	}
+	return true
+}
+
+// acceptsGzip reports whether request's Accept-Encoding header permits gzip
+// content coding.
+func acceptsGzip(r *http.Request) bool {
+	for _, v := range r.Header["Accept-Encoding"] {
+		for _, coding := range strings.Split(v, ",") {
+			var params string
+			if i := strings.IndexByte(coding, ';'); i >= 0 {
+				coding, params = coding[:i], coding[i+1:]
+			}
+			if strings.TrimSpace(coding) != "gzip" {
+				continue
+			}
+			if params = strings.TrimSpace(params); strings.HasPrefix(params, "q=") {
+				q, err := strconv.ParseFloat(params[2:], 64)
+				return err == nil && q > 0
+			}
+			return true
+		}
+	}
+	return false
+}
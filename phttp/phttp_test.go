@@ -0,0 +1,136 @@
+package phttp
+
+import (
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var data = strings.Repeat("hello, world\n", 1000)
+
+func TestBuffer(t *testing.T) {
+	h := Buffer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusTeapot)
+		for i := 0; i < len(data); i += 100 {
+			io.WriteString(w, data[i:i+100])
+		}
+	}))
+	for i := 0; i < 2; i++ {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+		if rec.Code != http.StatusTeapot {
+			t.Errorf("unexpected status: %d; want %d", rec.Code, http.StatusTeapot)
+		}
+		if act, exp := rec.Header().Get("Content-Length"), strconv.Itoa(len(data)); act != exp {
+			t.Errorf("unexpected Content-Length: %q; want %q", act, exp)
+		}
+		if rec.Body.String() != data {
+			t.Errorf("unexpected body")
+		}
+	}
+}
+
+func TestBufferNoContent(t *testing.T) {
+	h := Buffer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("unexpected status: %d; want %d", rec.Code, http.StatusNoContent)
+	}
+	if act := rec.Header().Get("Content-Length"); act != "" {
+		t.Errorf("unexpected Content-Length: %q", act)
+	}
+}
+
+func TestBufferGzip(t *testing.T) {
+	h := BufferGzip(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, data)
+	}), gzip.BestSpeed)
+
+	for _, test := range []struct {
+		accept string
+		gzip   bool
+	}{
+		{"", false},
+		{"gzip", true},
+		{"deflate, gzip;q=1.0, *;q=0.5", true},
+		{"gzip;q=0", false},
+		{"deflate", false},
+	} {
+		t.Run(test.accept, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			if test.accept != "" {
+				req.Header.Set("Accept-Encoding", test.accept)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if act, exp := rec.Header().Get("Content-Length"), strconv.Itoa(rec.Body.Len()); act != exp {
+				t.Errorf("unexpected Content-Length: %q; want %q", act, exp)
+			}
+			if act, exp := rec.Header().Get("Content-Type"), "text/plain; charset=utf-8"; test.gzip && act != exp {
+				t.Errorf("unexpected Content-Type: %q; want %q", act, exp)
+			}
+			body := io.Reader(rec.Body)
+			if enc := rec.Header().Get("Content-Encoding"); test.gzip {
+				if enc != "gzip" {
+					t.Fatalf("unexpected Content-Encoding: %q; want gzip", enc)
+				}
+				zr, err := gzip.NewReader(body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				body = zr
+			} else if enc != "" {
+				t.Fatalf("unexpected Content-Encoding: %q", enc)
+			}
+			bts, err := ioutil.ReadAll(body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(bts) != data {
+				t.Errorf("unexpected body")
+			}
+		})
+	}
+}
+
+func TestBufferHead(t *testing.T) {
+	s := httptest.NewServer(Buffer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "42")
+		if r.Method != http.MethodHead {
+			io.WriteString(w, data[:42])
+		}
+	})))
+	defer s.Close()
+
+	for _, method := range []string{"HEAD", "GET"} {
+		req, _ := http.NewRequest(method, s.URL, nil)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.ContentLength != 42 {
+			t.Errorf("%s: unexpected Content-Length: %d; want 42", method, resp.ContentLength)
+		}
+	}
+}
+
+func TestBufferGzipInvalidLevel(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("want panic")
+		}
+	}()
+	BufferGzip(http.NotFoundHandler(), 42)
+}
@@ -0,0 +1,125 @@
+// Package pio contains io helpers whose scratch memory is taken from
+// pbytes.Default().
+package pio
+
+import (
+	"io"
+
+	"github.com/gobwas/pool/pbytes"
+)
+
+const copyBufferSize = 32 * 1024
+
+// DefaultBufferPool is BufferPool with 32KB buffers on top of
+// pbytes.Default().
+var DefaultBufferPool = NewBufferPool(nil, copyBufferSize)
+
+// BufferPool is an adapter of pbytes.Pool to the interface used by
+// httputil.ReverseProxy and alike:
+//
+//   type BufferPool interface {
+//       Get() []byte
+//       Put([]byte)
+//   }
+//
+// Its buffers could also be used as io.CopyBuffer() scratch memory.
+type BufferPool struct {
+	pool  *pbytes.Pool
+	size  int
+	exact bool
+}
+
+// NewBufferPool creates new BufferPool which returns buffers of at least size
+// bytes taken from p. Length of returned buffers is equal to their capacity,
+// that is, it is aware of the p size classes.
+// If p is nil, pbytes.Default() is used.
+func NewBufferPool(p *pbytes.Pool, size int) *BufferPool {
+	return &BufferPool{
+		pool: p,
+		size: size,
+	}
+}
+
+// NewFixedBufferPool creates new BufferPool which returns buffers of exactly
+// size bytes taken from p.
+// If p is nil, pbytes.Default() is used.
+func NewFixedBufferPool(p *pbytes.Pool, size int) *BufferPool {
+	return &BufferPool{
+		pool:  p,
+		size:  size,
+		exact: true,
+	}
+}
+
+// Get returns probably reused buffer.
+func (p *BufferPool) Get() []byte {
+	bts := p.bytes().GetLen(p.size)
+	if !p.exact {
+		bts = bts[:cap(bts)]
+	}
+	return bts
+}
+
+// Put takes buffer for future reuse.
+func (p *BufferPool) Put(bts []byte) {
+	p.bytes().Put(bts)
+}
+
+func (p *BufferPool) bytes() *pbytes.Pool {
+	if p.pool == nil {
+		return pbytes.Default()
+	}
+	return p.pool
+}
+
+// Copy works just like io.Copy() but takes its buffer from
+// pbytes.Default().
+func Copy(dst io.Writer, src io.Reader) (written int64, err error) {
+	if _, ok := src.(io.WriterTo); ok {
+		return io.Copy(dst, src)
+	}
+	if _, ok := dst.(io.ReaderFrom); ok {
+		return io.Copy(dst, src)
+	}
+	buf := DefaultBufferPool.Get()
+	written, err = io.CopyBuffer(dst, src, buf)
+	DefaultBufferPool.Put(buf)
+	return written, err
+}
+
+// CopyN works just like io.CopyN() but takes its buffer from
+// pbytes.Default().
+func CopyN(dst io.Writer, src io.Reader, n int64) (written int64, err error) {
+	written, err = Copy(dst, io.LimitReader(src, n))
+	if written == n {
+		return n, nil
+	}
+	if written < n && err == nil {
+		// src stopped early; must have been EOF.
+		err = io.EOF
+	}
+	return written, err
+}
+
+// ReadAll reads from r until an error or EOF and returns the data it read.
+// Returned slice is taken from pbytes.Default(), thus it could be returned
+// there by pbytes.Put() when it is no longer needed.
+func ReadAll(r io.Reader) ([]byte, error) {
+	bts := pbytes.GetCap(512)
+	for {
+		if len(bts) == cap(bts) {
+			grown := pbytes.GetCap(2 * cap(bts))
+			grown = append(grown, bts...)
+			pbytes.Put(bts)
+			bts = grown
+		}
+		n, err := r.Read(bts[len(bts):cap(bts)])
+		bts = bts[:len(bts)+n]
+		if err != nil {
+			if err == io.EOF {
+				err = nil
+			}
+			return bts, err
+		}
+	}
+}
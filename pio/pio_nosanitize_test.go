@@ -0,0 +1,21 @@
+//go:build !pool_sanitize
+// +build !pool_sanitize
+
+package pio
+
+import (
+	"testing"
+
+	"github.com/gobwas/pool/pbytes"
+)
+
+func TestBufferPool(t *testing.T) {
+	p := NewBufferPool(pbytes.New(0, 1024), 100)
+	if bts := p.Get(); len(bts) != 128 || cap(bts) != 128 {
+		t.Errorf("Get() returned %d-len %d-cap buffer; want 128-len 128-cap", len(bts), cap(bts))
+	}
+	p = NewFixedBufferPool(pbytes.New(0, 1024), 100)
+	if bts := p.Get(); len(bts) != 100 || cap(bts) != 128 {
+		t.Errorf("Get() returned %d-len %d-cap buffer; want 100-len 128-cap", len(bts), cap(bts))
+	}
+}
@@ -0,0 +1,96 @@
+package pio
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/gobwas/pool/pbytes"
+)
+
+var data = strings.Repeat("hello, world\n", 10000)
+
+func TestBufferPoolReverseProxy(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, data)
+	}))
+	defer backend.Close()
+
+	u, err := url.Parse(backend.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []httputil.BufferPool{
+		DefaultBufferPool,
+		NewFixedBufferPool(pbytes.New(0, 1024), 100),
+	} {
+		proxy := httputil.NewSingleHostReverseProxy(u)
+		proxy.BufferPool = p
+
+		front := httptest.NewServer(proxy)
+		resp, err := http.Get(front.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bts, err := ReadAll(resp.Body)
+		resp.Body.Close()
+		front.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(bts) != data {
+			t.Errorf("unexpected proxied data")
+		}
+		pbytes.Put(bts)
+	}
+}
+
+func TestCopy(t *testing.T) {
+	var buf bytes.Buffer
+	n, err := Copy(struct{ io.Writer }{&buf}, iotest.HalfReader(strings.NewReader(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(len(data)) || buf.String() != data {
+		t.Errorf("unexpected copied data")
+	}
+}
+
+func TestCopyN(t *testing.T) {
+	for _, test := range []struct {
+		n   int64
+		exp int64
+		err error
+	}{
+		{10, 10, nil},
+		{int64(len(data)), int64(len(data)), nil},
+		{int64(len(data)) + 1, int64(len(data)), io.EOF},
+	} {
+		n, err := CopyN(ioutil.Discard, iotest.OneByteReader(strings.NewReader(data)), test.n)
+		if n != test.exp || err != test.err {
+			t.Errorf("CopyN(%d) = %d, %v; want %d, %v", test.n, n, err, test.exp, test.err)
+		}
+	}
+}
+
+func TestReadAll(t *testing.T) {
+	bts, err := ReadAll(iotest.DataErrReader(strings.NewReader(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(bts) != data {
+		t.Errorf("unexpected data read")
+	}
+
+	_, err = ReadAll(iotest.TimeoutReader(strings.NewReader(data)))
+	if err != iotest.ErrTimeout {
+		t.Errorf("ReadAll() error is %v; want %v", err, iotest.ErrTimeout)
+	}
+}
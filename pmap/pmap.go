@@ -0,0 +1,108 @@
+// +build go1.21
+
+// Package pmap contains tools for pooling maps.
+package pmap
+
+import (
+	"github.com/gobwas/pool"
+)
+
+// maxGrowth is the number of times map could outgrow its size class and still
+// be reused.
+const maxGrowth = 2
+
+// Pool contains logic of reusing maps of various capacity.
+type Pool[K comparable, V any] struct {
+	pool *pool.Pool
+}
+
+// New creates new Pool that reuses maps which capacity is in logarithmic
+// range [min, max].
+//
+// Note that it is a shortcut for Custom() constructor with Options provided by
+// pool.WithLogSizeMapping() and pool.WithLogSizeRange(min, max) calls.
+func New[K comparable, V any](min, max int) *Pool[K, V] {
+	if min == 0 {
+		min = 1
+	}
+	return Custom[K, V](
+		pool.WithLogSizeMapping(),
+		pool.WithLogSizeRange(min, max),
+	)
+}
+
+// Custom creates new Pool with given options.
+func Custom[K comparable, V any](opts ...pool.Option) *Pool[K, V] {
+	return &Pool[K, V]{
+		pool: pool.Custom(opts...),
+	}
+}
+
+// Get returns probably reused empty map with room for at least n entries.
+// Note that n could be ceiled to the next power of two.
+func (p *Pool[K, V]) Get(n int) *Map[K, V] {
+	v, x := p.pool.Get(n)
+	if v != nil {
+		return v.(*Map[K, V])
+	}
+	return &Map[K, V]{
+		m:     make(map[K]V, x),
+		class: x,
+	}
+}
+
+// Put clears given map and takes it for future reuse.
+//
+// Maps whose peak number of entries went far beyond the size class they were
+// taken from are not reused, so they do not bloat the pool. Note that map
+// does not shrink when entries are deleted.
+func (p *Pool[K, V]) Put(m *Map[K, V]) {
+	m.track()
+	if m.peak > maxGrowth*m.class {
+		return
+	}
+	clear(m.m)
+	m.peak = 0
+	p.pool.Put(m, m.class)
+}
+
+// Map holds map taken from the Pool. It remembers the size class the map was
+// taken from and the peak number of entries it contained.
+type Map[K comparable, V any] struct {
+	m     map[K]V
+	class int
+	peak  int
+}
+
+// Map returns the underlying map.
+//
+// Note that the peak number of entries is tracked only by Set() calls and on
+// Put(); entries added and then deleted by direct map access are not
+// noticed.
+func (m *Map[K, V]) Map() map[K]V { return m.m }
+
+// Len returns number of entries in the map.
+func (m *Map[K, V]) Len() int { return len(m.m) }
+
+// Get returns value stored in the map by key k.
+func (m *Map[K, V]) Get(k K) (v V, ok bool) {
+	v, ok = m.m[k]
+	return v, ok
+}
+
+// Set stores value v in the map by key k.
+func (m *Map[K, V]) Set(k K, v V) {
+	m.m[k] = v
+	m.track()
+}
+
+// Delete deletes value stored in the map by key k.
+func (m *Map[K, V]) Delete(k K) {
+	delete(m.m, k)
+}
+
+func (m *Map[K, V]) track() {
+	if n := len(m.m); n > m.peak {
+		m.peak = n
+	}
+}
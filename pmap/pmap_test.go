@@ -0,0 +1,91 @@
+// +build go1.21
+
+package pmap
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/gobwas/pool"
+)
+
+func TestPoolGetPut(t *testing.T) {
+	var c pool.Counter
+	p := Custom[string, string](
+		pool.WithLogSizeMapping(),
+		pool.WithLogSizeRange(1, 16),
+		pool.WithObserver(&c),
+	)
+
+	m := p.Get(10)
+	m.Set("foo", "bar")
+	p.Put(m)
+	if m.Len() != 0 {
+		t.Fatalf("map was not cleared on Put()")
+	}
+	if s := c.Stats(); s.Puts != 1 || s.Drops != 0 {
+		t.Fatalf("want map to be taken for reuse; stats are %+v", s)
+	}
+
+	// Note that sync.Pool may drop the map (e.g. under the race detector), so
+	// Get() is not guaranteed to return m here.
+	act := p.Get(9)
+	if act.class != m.class {
+		t.Fatalf("unexpected size class: %d; want %d", act.class, m.class)
+	}
+	if act.Len() != 0 {
+		t.Fatalf("Get() returned non-empty map")
+	}
+	if act := p.Get(1); act == m {
+		t.Fatalf("unexpected reuse in other size class")
+	}
+}
+
+func TestPoolPutGrown(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		fill func(*Map[string, struct{}])
+	}{
+		{
+			name: "set",
+			fill: func(m *Map[string, struct{}]) {
+				for i := 0; i < 100; i++ {
+					m.Set(strconv.Itoa(i), struct{}{})
+				}
+			},
+		},
+		{
+			name: "set and delete",
+			fill: func(m *Map[string, struct{}]) {
+				for i := 0; i < 100; i++ {
+					m.Set(strconv.Itoa(i), struct{}{})
+				}
+				for i := 0; i < 99; i++ {
+					m.Delete(strconv.Itoa(i))
+				}
+			},
+		},
+		{
+			name: "direct",
+			fill: func(m *Map[string, struct{}]) {
+				for i := 0; i < 100; i++ {
+					m.Map()[strconv.Itoa(i)] = struct{}{}
+				}
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			p := New[string, struct{}](4, 16)
+
+			m := p.Get(4)
+			test.fill(m)
+			p.Put(m) // Should not reuse.
+
+			for _, n := range []int{4, 8, 16} {
+				if act := p.Get(n); act == m {
+					t.Fatalf("unexpected reuse")
+				}
+			}
+		})
+	}
+}
@@ -20,6 +20,22 @@
 //
 // There are non-generic implementations for pooling:
 // - pool/pbytes for []byte reuse;
+// - pool/pslice for []T reuse;
+// - pool/pmap for map[K]V reuse;
 // - pool/pbufio for *bufio.Reader and *bufio.Writer reuse;
+// - pool/pcompress for compress/{gzip,flate,zlib} readers and writers reuse;
+// - pool/pencoding for encoding/{json,binary} helpers on top of pbytes;
+// - pool/phash for hash.Hash reuse;
+// - pool/ptime for *time.Timer reuse;
+// - pool/pio for io helpers and httputil.BufferPool on top of pbytes;
+// - pool/phttp for net/http responses buffering on top of pbytes;
+//
+// Subpackage pool/parena allows to return objects pulled from the different
+// pools back at once.
+//
+// Subpackage pool/ptrace provides Observer integrating pools with
+// runtime/trace and runtime/pprof.
+//
+// Subpackage pool/pdebug serves pools statistics at /debug/pools HTTP page.
 //
 package pool
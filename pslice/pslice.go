@@ -0,0 +1,77 @@
+// +build go1.18
+
+// Package pslice contains tools for pooling slices of any element type.
+package pslice
+
+import "github.com/gobwas/pool"
+
+// Pool contains logic of reusing slices of T of various size.
+type Pool[T any] struct {
+	pool *pool.Pool
+}
+
+// New creates new Pool that reuses slices which size is in logarithmic range
+// [min, max].
+//
+// Note that it is a shortcut for Custom() constructor with Options provided by
+// pool.WithLogSizeMapping() and pool.WithLogSizeRange(min, max) calls.
+func New[T any](min, max int) *Pool[T] {
+	return Custom[T](
+		pool.WithLogSizeMapping(),
+		pool.WithLogSizeRange(min, max),
+	)
+}
+
+// Custom creates new Pool with given options.
+// Note that WithReset() and WithResetter() options are overridden by the
+// Pool, which zeroes slices on Put().
+func Custom[T any](opts ...pool.Option) *Pool[T] {
+	opts = append(opts[:len(opts):len(opts)], pool.WithReset(zero[T]))
+	return &Pool[T]{pool.Custom(opts...)}
+}
+
+// Get returns probably reused slice of T with at least capacity of c and
+// exactly len of n.
+func (p *Pool[T]) Get(n, c int) []T {
+	if n > c {
+		panic("requested length is greater than capacity")
+	}
+
+	v, x := p.pool.Get(c)
+	if v != nil {
+		s := v.([]T)
+		s = s[:n]
+		return s
+	}
+
+	return make([]T, n, x)
+}
+
+// Put returns given slice to reuse pool.
+// It does not reuse slices whose size is not power of two or is out of pool
+// min/max range. Otherwise it zeroes all elements of the slice up to its
+// capacity, so the values they were referencing could be collected by GC.
+func (p *Pool[T]) Put(s []T) {
+	p.pool.Put(s, cap(s))
+}
+
+// GetCap returns probably reused slice of T with at least capacity of c.
+func (p *Pool[T]) GetCap(c int) []T {
+	return p.Get(0, c)
+}
+
+// GetLen returns probably reused slice of T with at least capacity of n and
+// exactly len of n.
+func (p *Pool[T]) GetLen(n int) []T {
+	return p.Get(n, n)
+}
+
+// zero zeroes all elements of x up to its capacity. x must be a []T.
+func zero[T any](x interface{}) {
+	s := x.([]T)
+	s = s[:cap(s)]
+	var z T
+	for i := range s {
+		s[i] = z
+	}
+}
@@ -0,0 +1,105 @@
+// +build go1.18
+
+package pslice
+
+import "testing"
+
+func TestPoolGet(t *testing.T) {
+	for _, test := range []struct {
+		min      int
+		max      int
+		len      int
+		cap      int
+		exactCap int
+	}{
+		{
+			min:      0,
+			max:      64,
+			len:      10,
+			cap:      24,
+			exactCap: 32,
+		},
+		{
+			min:      0,
+			max:      0,
+			len:      10,
+			cap:      24,
+			exactCap: 24,
+		},
+	} {
+		t.Run("", func(t *testing.T) {
+			p := New[int64](test.min, test.max)
+			act := p.Get(test.len, test.cap)
+			if n := len(act); n != test.len {
+				t.Errorf(
+					"Get(%d, _) retured %d-len slice; want %[1]d",
+					test.len, n,
+				)
+			}
+			if c := cap(act); c < test.cap {
+				t.Errorf(
+					"Get(_, %d) retured %d-cap slice; want at least %[1]d",
+					test.cap, c,
+				)
+			}
+			if c := cap(act); test.exactCap != 0 && c != test.exactCap {
+				t.Errorf(
+					"Get(_, %d) retured %d-cap slice; want exact %d",
+					test.cap, c, test.exactCap,
+				)
+			}
+		})
+	}
+}
+
+func TestPoolPutClears(t *testing.T) {
+	p := New[*int](0, 8)
+
+	s := make([]*int, 2, 8)
+	for i := range s[:cap(s)] {
+		s[:cap(s)][i] = new(int)
+	}
+	p.Put(s)
+
+	for i, x := range s[:cap(s)] {
+		if x != nil {
+			t.Errorf("element #%d was not cleared on Put()", i)
+		}
+	}
+}
+
+func TestPoolPutReuse(t *testing.T) {
+	p := New[string](0, 32)
+
+	miss := make([]string, 5)
+	p.Put(miss) // Should not reuse.
+
+	hit := make([]string, 8)
+	p.Put(hit) // Should reuse.
+
+	// Note that sync.Pool may drop the hit slice (e.g. under the race
+	// detector), so we only check that the miss slice is never reused.
+	s := p.GetLen(5)
+	if &s[:1][0] == &miss[0] {
+		t.Fatalf("unexpected reuse")
+	}
+	if c := cap(s); c != 8 {
+		t.Fatalf("unexpected capacity: %d; want 8", c)
+	}
+}
+
+func TestPoolPutKeepsUnreused(t *testing.T) {
+	p := New[*int](0, 8)
+
+	s := make([]*int, 5)
+	for i := range s {
+		s[i] = new(int)
+	}
+	p.Put(s) // Should not reuse.
+
+	for i, x := range s {
+		if x == nil {
+			t.Errorf("element #%d of not reused slice was cleared on Put()", i)
+		}
+	}
+}
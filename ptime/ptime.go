@@ -0,0 +1,37 @@
+// Package ptime contains tools for pooling time.Timer.
+package ptime
+
+import (
+	"sync"
+	"time"
+)
+
+var timers sync.Pool
+
+// AcquireTimer returns probably reused time.Timer that will send the current
+// time on its channel after at least duration d.
+//
+// Returned timer must not be used after ReleaseTimer() call.
+func AcquireTimer(d time.Duration) *time.Timer {
+	t, _ := timers.Get().(*time.Timer)
+	if t == nil {
+		return time.NewTimer(d)
+	}
+	t.Reset(d)
+	return t
+}
+
+// ReleaseTimer stops t and takes it for future reuse. It is safe to call
+// ReleaseTimer() no matter whether t has fired and whether its value was
+// received from the channel.
+func ReleaseTimer(t *time.Timer) {
+	if !t.Stop() {
+		// Timer has already fired. Drain its channel in case the value was
+		// not received yet so the next user will not get stale value.
+		select {
+		case <-t.C:
+		default:
+		}
+	}
+	timers.Put(t)
+}
@@ -0,0 +1,53 @@
+package ptime
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTimerFire(t *testing.T) {
+	tm := AcquireTimer(time.Millisecond)
+	select {
+	case <-tm.C:
+	case <-time.After(time.Second):
+		t.Fatalf("timer did not fire")
+	}
+	ReleaseTimer(tm)
+}
+
+func TestTimerStaleValue(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(seed))
+			for j := 0; j < 200; j++ {
+				d := time.Duration(r.Intn(100)) * time.Microsecond
+				tm := AcquireTimer(d)
+				switch r.Intn(3) {
+				case 0:
+					// Wait for the timer to fire and receive the value.
+					<-tm.C
+				case 1:
+					// Let the timer fire, but do not receive the value.
+					time.Sleep(d + 50*time.Microsecond)
+				case 2:
+					// Stop the timer probably before it fires.
+				}
+				ReleaseTimer(tm)
+
+				tm = AcquireTimer(time.Hour)
+				select {
+				case <-tm.C:
+					t.Errorf("stale value received from reused timer")
+				default:
+				}
+				ReleaseTimer(tm)
+			}
+		}(int64(i))
+	}
+	wg.Wait()
+}
@@ -0,0 +1,168 @@
+// Package ptrace contains pool.Observer integrating pools with runtime/trace
+// and runtime/pprof.
+//
+// Quick example:
+//
+//   import (
+//       _ "net/http/pprof"
+//
+//       "github.com/gobwas/pool"
+//       "github.com/gobwas/pool/pbytes"
+//       "github.com/gobwas/pool/ptrace"
+//   )
+//
+//   var bytesPool = pbytes.Custom(
+//       pool.WithLogSizeMapping(),
+//       pool.WithLogSizeRange(128, 65536),
+//       pool.WithObserver(ptrace.NewObserver("bytes")),
+//   )
+//
+// Then objects taken from the bytesPool but not returned yet could be
+// inspected by `go tool pprof http://host/debug/pprof/github.com/gobwas/pool/bytes`.
+package ptrace
+
+import (
+	"context"
+	"reflect"
+	"runtime/pprof"
+	"runtime/trace"
+	"strconv"
+	"sync"
+	"unsafe"
+)
+
+// ProfilePrefix is prepended to names of profiles created by NewObserver().
+const ProfilePrefix = "github.com/gobwas/pool/"
+
+// Observer implements pool.Observer and pool.Tracker interfaces. It logs pool
+// misses and drops to the runtime/trace when tracing is enabled. It also
+// maintains pprof profile of objects acquired from the pool but not released
+// yet by their acquisition stacks.
+//
+// Note that Observer holds references to acquired objects until they are
+// released. That is, objects which are never returned to the pool are never
+// collected by GC. Observer is intended for debugging and should not be used
+// with pools whose objects are not always returned.
+type Observer struct {
+	name string
+	*tracker
+}
+
+// tracker holds pprof profile shared by observers with the same name.
+type tracker struct {
+	profile *pprof.Profile
+
+	mu       sync.Mutex
+	acquired map[interface{}]struct{}
+}
+
+var profiles struct {
+	mu sync.Mutex
+	m  map[string]*tracker
+}
+
+// NewObserver creates new Observer with given name. It registers pprof
+// profile named with ProfilePrefix followed by name. Observers created with
+// the same name share the same profile. It panics if a profile with such
+// name was already registered not by NewObserver().
+func NewObserver(name string) *Observer {
+	profiles.mu.Lock()
+	defer profiles.mu.Unlock()
+	t := profiles.m[name]
+	if t == nil {
+		t = &tracker{
+			profile:  pprof.NewProfile(ProfilePrefix + name),
+			acquired: make(map[interface{}]struct{}),
+		}
+		if profiles.m == nil {
+			profiles.m = make(map[string]*tracker)
+		}
+		profiles.m[name] = t
+	}
+	return &Observer{
+		name:    name,
+		tracker: t,
+	}
+}
+
+// Profile returns pprof profile of objects acquired from the pool but not
+// released yet.
+func (o *Observer) Profile() *pprof.Profile {
+	return o.profile
+}
+
+// OnGet implements pool.Observer.
+func (o *Observer) OnGet(size, class int, hit bool) {
+	if !hit && trace.IsEnabled() {
+		trace.Log(context.Background(), "pool miss", o.name+
+			": size="+strconv.Itoa(size)+
+			" class="+strconv.Itoa(class),
+		)
+	}
+}
+
+// OnPut implements pool.Observer.
+func (o *Observer) OnPut(size int, accepted bool) {
+	if !accepted && trace.IsEnabled() {
+		trace.Log(context.Background(), "pool drop", o.name+
+			": size="+strconv.Itoa(size),
+		)
+	}
+}
+
+// OnAlloc implements pool.Observer.
+func (o *Observer) OnAlloc(size int) {}
+
+// OnAcquire implements pool.Tracker.
+func (o *Observer) OnAcquire(x interface{}) {
+	k := key(x)
+	if k == nil {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if _, has := o.acquired[k]; has {
+		// Object was put twice and then reused twice.
+		return
+	}
+	o.acquired[k] = struct{}{}
+	o.profile.Add(k, 1)
+}
+
+// OnRelease implements pool.Tracker.
+func (o *Observer) OnRelease(x interface{}) {
+	k := key(x)
+	if k == nil {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if _, has := o.acquired[k]; has {
+		delete(o.acquired, k)
+		o.profile.Remove(k)
+	}
+}
+
+// key returns comparable key identifying x. It returns nil if x could not be
+// identified. Returned key references memory of x, so it could not be reused
+// by GC while the key is held.
+func key(x interface{}) interface{} {
+	if x == nil {
+		return nil
+	}
+	v := reflect.ValueOf(x)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		// Slices and maps are not comparable, so they are identified by their
+		// underlying memory. Note that it is held by unsafe.Pointer rather
+		// than uintptr, so the address is not reused while being tracked.
+		if p := unsafe.Pointer(v.Pointer()); p != nil {
+			return p
+		}
+		return nil
+	}
+	if v.Type().Comparable() {
+		return x
+	}
+	return nil
+}
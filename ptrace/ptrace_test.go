@@ -0,0 +1,118 @@
+// +build !pool_sanitize
+
+package ptrace
+
+import (
+	"io/ioutil"
+	"runtime/trace"
+	"strings"
+	"testing"
+
+	"github.com/gobwas/pool"
+	"github.com/gobwas/pool/pbufio"
+	"github.com/gobwas/pool/pbytes"
+)
+
+func TestObserverProfile(t *testing.T) {
+	o := NewObserver(t.Name())
+	p := pbytes.Custom(
+		pool.WithLogSizeMapping(),
+		pool.WithLogSizeRange(0, 64),
+		pool.WithObserver(o),
+	)
+
+	h := p.Acquire(10, 10)
+	if n := o.Profile().Count(); n != 1 {
+		t.Fatalf("profile contains %d objects; want %d", n, 1)
+	}
+	h.Release()
+	if n := o.Profile().Count(); n != 0 {
+		t.Fatalf("profile contains %d objects; want %d", n, 0)
+	}
+
+	bts := p.GetLen(10) // Allocated slice must be tracked.
+	if n := o.Profile().Count(); n != 1 {
+		t.Fatalf("profile contains %d objects; want %d", n, 1)
+	}
+	p.Put(bts)
+	if n := o.Profile().Count(); n != 0 {
+		t.Fatalf("profile contains %d objects; want %d", n, 0)
+	}
+
+	p.Put(make([]byte, 16))
+	bts = p.GetLen(10) // Reused slice must be tracked.
+	if n := o.Profile().Count(); n != 1 {
+		t.Fatalf("profile contains %d objects; want %d", n, 1)
+	}
+	p.Put(bts)
+	if n := o.Profile().Count(); n != 0 {
+		t.Fatalf("profile contains %d objects; want %d", n, 0)
+	}
+}
+
+func TestObserverTrace(t *testing.T) {
+	if err := trace.Start(ioutil.Discard); err != nil {
+		t.Skipf("could not start trace: %v", err)
+	}
+	defer trace.Stop()
+
+	o := NewObserver(t.Name())
+	p := pool.Custom(
+		pool.WithLogSizeMapping(),
+		pool.WithLogSizeRange(0, 64),
+		pool.WithObserver(o),
+	)
+	_, n := p.Get(100)
+	p.Put(new(int), n)
+}
+
+func TestKey(t *testing.T) {
+	x := new(int)
+	bts := make([]byte, 10)
+	for _, test := range []struct {
+		name string
+		in   interface{}
+		nil  bool
+	}{
+		{"nil", nil, true},
+		{"pointer", x, false},
+		{"slice", bts, false},
+		{"empty slice", []byte(nil), true},
+		{"map", map[int]int{}, false},
+		{"func", func() {}, true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if k := key(test.in); (k == nil) != test.nil {
+				t.Errorf("key(%v) = %v", test.in, k)
+			}
+		})
+	}
+	if key(bts) != key(bts[:0]) {
+		t.Errorf("unexpected key of resliced slice")
+	}
+}
+
+func TestObserverProfileBufio(t *testing.T) {
+	o := NewObserver(t.Name())
+	p := pbufio.CustomReaderPool(
+		pool.WithLogSizeMapping(),
+		pool.WithLogSizeRange(16, 64),
+		pool.WithObserver(o),
+	)
+	br := p.Get(strings.NewReader(""), 16) // Allocated reader must be tracked.
+	if n := o.Profile().Count(); n != 1 {
+		t.Fatalf("profile contains %d objects; want %d", n, 1)
+	}
+	p.Put(br)
+	if n := o.Profile().Count(); n != 0 {
+		t.Fatalf("profile contains %d objects; want %d", n, 0)
+	}
+}
+
+func TestNewObserverSameName(t *testing.T) {
+	a := NewObserver(t.Name())
+	b := NewObserver(t.Name())
+	if a.Profile() != b.Profile() {
+		t.Errorf("observers with the same name have different profiles")
+	}
+}
@@ -0,0 +1,74 @@
+package pool
+
+import (
+	"sort"
+	"sync"
+)
+
+var registry struct {
+	mu    sync.RWMutex
+	pools map[string]*Pool
+}
+
+// register adds p to the registry by its name.
+// It panics if there is a pool with the same name already registered.
+func register(p *Pool) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	if _, has := registry.pools[p.name]; has {
+		panic("pool: reuse of registered pool name: " + p.name)
+	}
+	if registry.pools == nil {
+		registry.pools = make(map[string]*Pool)
+	}
+	registry.pools[p.name] = p
+}
+
+// Unregister removes the pool registered by given name from the registry, so
+// the name could be used again. It returns false if there is no such pool
+// registered.
+func Unregister(name string) bool {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	_, has := registry.pools[name]
+	delete(registry.pools, name)
+	return has
+}
+
+// Lookup returns the pool registered by given name. It returns nil if there
+// is no such pool registered.
+//
+// Note that pools created by pbytes.Custom() or pbufio.Custom*Pool()
+// constructors are registered as their underlying generic Pool.
+func Lookup(name string) *Pool {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	return registry.pools[name]
+}
+
+// Names returns sorted list of registered pool names.
+func Names() []string {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	names := make([]string, 0, len(registry.pools))
+	for name := range registry.pools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Range calls fn for each registered pool in order of their names. If fn
+// returns false, Range stops the iteration.
+func Range(fn func(name string, p *Pool) bool) {
+	for _, name := range Names() {
+		p := Lookup(name)
+		if p == nil {
+			// Pool was unregistered during iteration.
+			continue
+		}
+		if !fn(name, p) {
+			return
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package pool
+
+import (
+	"reflect"
+	"testing"
+)
+
+var testNamedPool = Custom(
+	WithName("pool.test"),
+	WithLogSizeMapping(),
+	WithLogSizeRange(0, 8),
+)
+
+func TestRegistry(t *testing.T) {
+	if p := Lookup("pool.test"); p != testNamedPool {
+		t.Errorf("Lookup() = %p; want %p", p, testNamedPool)
+	}
+	if p := Lookup("pool.DefaultPool"); p != DefaultPool {
+		t.Errorf("Lookup() = %p; want DefaultPool", p)
+	}
+	if p := Lookup("pool.unknown"); p != nil {
+		t.Errorf("Lookup() = %p; want nil", p)
+	}
+	if name := testNamedPool.Name(); name != "pool.test" {
+		t.Errorf("Name() = %q; want %q", name, "pool.test")
+	}
+	if name := New(0, 8).Name(); name != "" {
+		t.Errorf("Name() = %q; want empty", name)
+	}
+
+	var names []string
+	Range(func(name string, p *Pool) bool {
+		if p.Name() != name {
+			t.Errorf("Range() pool with name %q for %q", p.Name(), name)
+		}
+		names = append(names, name)
+		return true
+	})
+	if exp := Names(); !reflect.DeepEqual(names, exp) {
+		t.Errorf("Range() iterated over %v; want %v", names, exp)
+	}
+
+	var n int
+	Range(func(string, *Pool) bool {
+		n++
+		return false
+	})
+	if n != 1 {
+		t.Errorf("Range() did not stop the iteration")
+	}
+}
+
+func TestRegistryDuplicate(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("want panic")
+		}
+	}()
+	Custom(WithName("pool.DefaultPool"))
+}
+
+func TestUnregister(t *testing.T) {
+	p := Custom(WithName("pool.test.unregister"))
+	if Lookup("pool.test.unregister") != p {
+		t.Fatalf("pool was not registered")
+	}
+	if !Unregister("pool.test.unregister") {
+		t.Errorf("Unregister() = false; want true")
+	}
+	if Lookup("pool.test.unregister") != nil {
+		t.Errorf("Lookup() returns unregistered pool")
+	}
+	if Unregister("pool.test.unregister") {
+		t.Errorf("Unregister() = true for unregistered pool")
+	}
+
+	// Name could be used again.
+	Custom(WithName("pool.test.unregister"))
+	Unregister("pool.test.unregister")
+}